@@ -9,36 +9,27 @@ resource "tetration_filter" "filter" {
   name         = "Terraform created filter"
   query        = <<EOF
             {
-               "type": "and",
+               "type": "or",
                "filters": [
                   {
-                     "field": "vrf_id",
+                     "field": "ip",
                      "type": "eq",
-                     "value": 700056
+                     "value": "10.254.252.43"
                   },
                   {
-                     "type": "or",
-                     "filters": [
-                        {
-                           "field": "ip",
-                           "type": "eq",
-                           "value": "10.254.252.43"
-                        },
-                        {
-                           "field": "ip",
-                           "type": "eq",
-                           "value": "10.254.252.51"
-                        },
-                        {
-                           "field": "ip",
-                           "type": "eq",
-                           "value": "10.254.252.52"
-                        }
-                     ]
+                     "field": "ip",
+                     "type": "eq",
+                     "value": "10.254.252.51"
+                  },
+                  {
+                     "field": "ip",
+                     "type": "eq",
+                     "value": "10.254.252.52"
                   }
                ]
             }
           EOF
+  vrf_name     = "Production"
   app_scope_id = "5ed6890c497d4f55eb5c585c"
   primary      = true
   public       = false
@@ -6,4 +6,6 @@ require (
 	github.com/hashicorp/go-multierror v1.1.0
 	github.com/hashicorp/terraform v0.12.25
 	github.com/tetration-exchange/terraform-go-sdk v0.0.1
+	github.com/zclconf/go-cty v1.2.1
+	github.com/zclconf/go-cty-yaml v1.0.1
 )
@@ -0,0 +1,113 @@
+package tetration
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+	client "github.com/tetration-exchange/terraform-go-sdk"
+)
+
+func resourceTetrationAgentCleanup() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceTetrationAgentCleanupCreate,
+		Read:   resourceTetrationAgentCleanupRead,
+		Delete: resourceTetrationAgentCleanupDelete,
+
+		SchemaVersion: 1,
+
+		Schema: map[string]*schema.Schema{
+			"scope_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "ID of the scope whose workloads' stale agents are deleted. Only workloads still in the inventory of the scope are matched.",
+			},
+			"inactive_days": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Number of days since their last check-in after which agents are deleted.",
+			},
+			"triggers": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				ForceNew: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "(Optional) Arbitrary values that run the cleanup again when changed, e.g. a timestamp.",
+			},
+			"deleted_agents": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "UUIDs of the agents that were deleted.",
+			},
+		},
+	}
+}
+
+func deleteAgent(apiClient client.Client, agentUuid string) error {
+	return doRequest(apiClient, http.MethodDelete, fmt.Sprintf("/sensors/%s", agentUuid), nil, nil)
+}
+
+// staleAgents returns the agents in scopeUuids that last checked in more than
+// inactiveAfter before now. Agents whose last check-in is unknown are never stale.
+func staleAgents(agents []agent, scopeUuids map[string]bool, inactiveAfter time.Duration, now time.Time) []agent {
+	var stale []agent
+	for _, a := range agents {
+		if !scopeUuids[a.Uuid] || a.LastConfigFetchAt <= 0 || a.status(inactiveAfter, now) != InactiveAgentStatus {
+			continue
+		}
+		stale = append(stale, a)
+	}
+	return stale
+}
+
+func resourceTetrationAgentCleanupCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(providerMeta).Client
+	scopeId := d.Get("scope_id").(string)
+	scopeUuids, err := agentUuidsInScope(client, scopeId)
+	if err != nil {
+		return err
+	}
+	agents, err := listAgents(client)
+	if err != nil {
+		return err
+	}
+	inactiveAfter := time.Duration(d.Get("inactive_days").(int)) * 24 * time.Hour
+	now := time.Now()
+	deleted := []string{}
+	var failures []string
+	for _, a := range staleAgents(agents, scopeUuids, inactiveAfter, now) {
+		if err := deleteAgent(client, a.Uuid); err != nil {
+			failures = append(failures, fmt.Sprintf("%s (%s): %s", a.Uuid, a.HostName, err))
+			continue
+		}
+		deleted = append(deleted, a.Uuid)
+	}
+	d.SetId(fmt.Sprintf("%s:%d", scopeId, now.Unix()))
+	d.Set("deleted_agents", deleted)
+	if len(failures) > 0 {
+		return fmt.Errorf("Failed to delete %d agents:\n%s", len(failures), strings.Join(failures, "\n"))
+	}
+	return nil
+}
+
+// resourceTetrationAgentCleanupRead keeps the agents deleted by the cleanup in state.
+func resourceTetrationAgentCleanupRead(d *schema.ResourceData, meta interface{}) error {
+	return nil
+}
+
+// resourceTetrationAgentCleanupDelete only removes the cleanup from state,
+// deleted agents re-register when they check in again.
+func resourceTetrationAgentCleanupDelete(d *schema.ResourceData, meta interface{}) error {
+	return nil
+}
@@ -0,0 +1,192 @@
+package tetration
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	client "github.com/tetration-exchange/terraform-go-sdk"
+)
+
+func resourceTetrationAgentConfigIntent() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceTetrationAgentConfigIntentCreate,
+		Update: resourceTetrationAgentConfigIntentUpdate,
+		Read:   resourceTetrationAgentConfigIntentRead,
+		Delete: resourceTetrationAgentConfigIntentDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		SchemaVersion: 1,
+
+		Schema: map[string]*schema.Schema{
+			"filter_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "ID of the inventory filter matching the agents the profile is applied to.",
+			},
+			"profile_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "ID of the agent config profile applied to the matching agents.",
+			},
+		},
+	}
+}
+
+func resourceTetrationAgentConfigIntentOrder() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceTetrationAgentConfigIntentOrderCreate,
+		Update: resourceTetrationAgentConfigIntentOrderCreate,
+		Read:   resourceTetrationAgentConfigIntentOrderRead,
+		Delete: resourceTetrationAgentConfigIntentOrderDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		SchemaVersion: 1,
+
+		Schema: map[string]*schema.Schema{
+			"root_app_scope_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "ID of the root scope whose agent config intents are ordered.",
+			},
+			"intent_ids": {
+				Type:     schema.TypeList,
+				Required: true,
+				MinItems: 1,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "IDs of all the agent config intents of the root scope, highest priority first. Agents matching several intents get the profile of the first.",
+			},
+		},
+	}
+}
+
+// agentConfigIntent describes the application of an agent config
+// profile to the agents matching an inventory filter.
+type agentConfigIntent struct {
+	Id        string `json:"id,omitempty"`
+	ProfileId string `json:"inventory_config_profile_id"`
+	FilterId  string `json:"inventory_filter_id"`
+}
+
+// agentConfigIntentOrder describes the priority order of the agent config intents of a root scope.
+type agentConfigIntentOrder struct {
+	RootAppScopeId string   `json:"root_app_scope_id,omitempty"`
+	IntentIds      []string `json:"intent_ids"`
+}
+
+// createAgentConfigIntent creates an agent config intent, returning the created
+// intent.
+func createAgentConfigIntent(apiClient client.Client, params agentConfigIntent) (agentConfigIntent, error) {
+	var intent agentConfigIntent
+	err := doRequest(apiClient, http.MethodPost, "/inventory_config/intents", params, &intent)
+	return intent, err
+}
+
+func describeAgentConfigIntent(apiClient client.Client, intentId string) (agentConfigIntent, error) {
+	var intent agentConfigIntent
+	err := doRequest(apiClient, http.MethodGet, fmt.Sprintf("/inventory_config/intents/%s", intentId), nil, &intent)
+	return intent, err
+}
+
+func updateAgentConfigIntent(apiClient client.Client, intentId string, params agentConfigIntent) error {
+	return doRequest(apiClient, http.MethodPut, fmt.Sprintf("/inventory_config/intents/%s", intentId), params, nil)
+}
+
+func deleteAgentConfigIntent(apiClient client.Client, intentId string) error {
+	return doRequest(apiClient, http.MethodDelete, fmt.Sprintf("/inventory_config/intents/%s", intentId), nil, nil)
+}
+
+// describeAgentConfigIntentOrder returns the priority order of the agent config
+// intents of a root scope.
+func describeAgentConfigIntentOrder(apiClient client.Client, rootAppScopeId string) (agentConfigIntentOrder, error) {
+	var order agentConfigIntentOrder
+	err := doRequest(apiClient, http.MethodGet, "/inventory_config/orders?root_app_scope_id="+url.QueryEscape(rootAppScopeId), nil, &order)
+	return order, err
+}
+
+// updateAgentConfigIntentOrder replaces the priority order of the agent config
+// intents of a root scope.
+func updateAgentConfigIntentOrder(apiClient client.Client, params agentConfigIntentOrder) error {
+	return doRequest(apiClient, http.MethodPost, "/inventory_config/orders", params, nil)
+}
+
+func resourceTetrationAgentConfigIntentCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(providerMeta).Client
+	intent, err := createAgentConfigIntent(client, agentConfigIntent{
+		ProfileId: d.Get("profile_id").(string),
+		FilterId:  d.Get("filter_id").(string),
+	})
+	if err != nil {
+		return err
+	}
+	d.SetId(intent.Id)
+	return resourceTetrationAgentConfigIntentRead(d, meta)
+}
+
+func resourceTetrationAgentConfigIntentUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(providerMeta).Client
+	err := updateAgentConfigIntent(client, d.Id(), agentConfigIntent{
+		ProfileId: d.Get("profile_id").(string),
+		FilterId:  d.Get("filter_id").(string),
+	})
+	if err != nil {
+		return err
+	}
+	return resourceTetrationAgentConfigIntentRead(d, meta)
+}
+
+func resourceTetrationAgentConfigIntentRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(providerMeta).Client
+	intent, err := describeAgentConfigIntent(client, d.Id())
+	if err != nil {
+		return err
+	}
+	d.Set("profile_id", intent.ProfileId)
+	d.Set("filter_id", intent.FilterId)
+	return nil
+}
+
+func resourceTetrationAgentConfigIntentDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(providerMeta).Client
+	return deleteAgentConfigIntent(client, d.Id())
+}
+
+func resourceTetrationAgentConfigIntentOrderCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(providerMeta).Client
+	rootAppScopeId := d.Get("root_app_scope_id").(string)
+	err := updateAgentConfigIntentOrder(client, agentConfigIntentOrder{
+		RootAppScopeId: rootAppScopeId,
+		IntentIds:      stringsFromTerraform(d.Get("intent_ids").([]interface{})),
+	})
+	if err != nil {
+		return err
+	}
+	d.SetId(rootAppScopeId)
+	return resourceTetrationAgentConfigIntentOrderRead(d, meta)
+}
+
+func resourceTetrationAgentConfigIntentOrderRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(providerMeta).Client
+	order, err := describeAgentConfigIntentOrder(client, d.Id())
+	if err != nil {
+		return err
+	}
+	d.Set("root_app_scope_id", d.Id())
+	d.Set("intent_ids", order.IntentIds)
+	return nil
+}
+
+// resourceTetrationAgentConfigIntentOrderDelete only removes the order from state,
+// the intents of a root scope always have an order.
+func resourceTetrationAgentConfigIntentOrderDelete(d *schema.ResourceData, meta interface{}) error {
+	return nil
+}
@@ -0,0 +1,194 @@
+package tetration
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+	client "github.com/tetration-exchange/terraform-go-sdk"
+)
+
+const (
+	// CPU quota mode limiting agents to a percentage of the CPU of the host.
+	CpuQuotaModeHostPercentage = 1
+)
+
+func resourceTetrationAgentConfigProfile() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceTetrationAgentConfigProfileCreate,
+		Update: resourceTetrationAgentConfigProfileUpdate,
+		Read:   resourceTetrationAgentConfigProfileRead,
+		Delete: resourceTetrationAgentConfigProfileDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		SchemaVersion: 1,
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "User-specified name for the agent config profile.",
+			},
+			"root_app_scope_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "ID of the root scope the profile belongs to.",
+			},
+			"enforcement_enabled": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "(Optional) Whether agents enforce policies. Default value is false.",
+			},
+			"preserve_rules": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "(Optional) Whether agents preserve the existing firewall rules of the host when enforcing. Default value is false.",
+			},
+			"cpu_limit_percent": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      3,
+				ValidateFunc: validation.IntBetween(1, 100),
+				Description:  "(Optional) Maximum percentage of the CPU of the host agents can use. Default value is 3.",
+			},
+			"memory_limit_mb": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      512,
+				ValidateFunc: validation.IntAtLeast(128),
+				Description:  "(Optional) Maximum memory in megabytes agents can use. Default value is 512.",
+			},
+			"data_plane_enabled": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "(Optional) Whether agents capture flow telemetry. Default value is true.",
+			},
+			"forensics_enabled": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "(Optional) Whether agents collect forensic events. Default value is false.",
+			},
+			"process_tracking_enabled": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "(Optional) Whether agents track the processes of flows. Default value is false.",
+			},
+			"auto_upgrade_enabled": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "(Optional) Whether agents are automatically upgraded to new versions. Default value is true.",
+			},
+		},
+	}
+}
+
+// agentConfigProfile describes a profile of configuration settings for agents.
+type agentConfigProfile struct {
+	Id                  string `json:"id,omitempty"`
+	Name                string `json:"name"`
+	RootAppScopeId      string `json:"root_app_scope_id"`
+	EnforcementDisabled bool   `json:"enforcement_disabled"`
+	PreserveRules       bool   `json:"preserve_rules"`
+	CpuQuotaMode        int    `json:"cpu_quota_mode"`
+	CpuQuotaPercent     int    `json:"cpu_quota_pct"`
+	// Memory limit in megabytes.
+	MaxRssLimit       int  `json:"max_rss_limit"`
+	DataPlaneDisabled bool `json:"data_plane_disabled"`
+	EnableForensics   bool `json:"enable_forensics"`
+	EnablePidLookup   bool `json:"enable_pid_lookup"`
+	AutoUpgradeOptOut bool `json:"auto_upgrade_opt_out"`
+}
+
+// createAgentConfigProfile creates an agent config profile, returning the
+// created profile.
+func createAgentConfigProfile(apiClient client.Client, params agentConfigProfile) (agentConfigProfile, error) {
+	var profile agentConfigProfile
+	err := doRequest(apiClient, http.MethodPost, "/inventory_config/profiles", params, &profile)
+	return profile, err
+}
+
+// describeAgentConfigProfile returns the agent config profile with the given
+// id.
+func describeAgentConfigProfile(apiClient client.Client, profileId string) (agentConfigProfile, error) {
+	var profile agentConfigProfile
+	err := doRequest(apiClient, http.MethodGet, fmt.Sprintf("/inventory_config/profiles/%s", profileId), nil, &profile)
+	return profile, err
+}
+
+func updateAgentConfigProfile(apiClient client.Client, profileId string, params agentConfigProfile) error {
+	return doRequest(apiClient, http.MethodPut, fmt.Sprintf("/inventory_config/profiles/%s", profileId), params, nil)
+}
+
+func deleteAgentConfigProfile(apiClient client.Client, profileId string) error {
+	return doRequest(apiClient, http.MethodDelete, fmt.Sprintf("/inventory_config/profiles/%s", profileId), nil, nil)
+}
+
+// agentConfigProfileFromTerraform returns the profile configured by the resource.
+func agentConfigProfileFromTerraform(d *schema.ResourceData) agentConfigProfile {
+	return agentConfigProfile{
+		Name:                d.Get("name").(string),
+		RootAppScopeId:      d.Get("root_app_scope_id").(string),
+		EnforcementDisabled: !d.Get("enforcement_enabled").(bool),
+		PreserveRules:       d.Get("preserve_rules").(bool),
+		CpuQuotaMode:        CpuQuotaModeHostPercentage,
+		CpuQuotaPercent:     d.Get("cpu_limit_percent").(int),
+		MaxRssLimit:         d.Get("memory_limit_mb").(int),
+		DataPlaneDisabled:   !d.Get("data_plane_enabled").(bool),
+		EnableForensics:     d.Get("forensics_enabled").(bool),
+		EnablePidLookup:     d.Get("process_tracking_enabled").(bool),
+		AutoUpgradeOptOut:   !d.Get("auto_upgrade_enabled").(bool),
+	}
+}
+
+func resourceTetrationAgentConfigProfileCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(providerMeta).Client
+	profile, err := createAgentConfigProfile(client, agentConfigProfileFromTerraform(d))
+	if err != nil {
+		return err
+	}
+	d.SetId(profile.Id)
+	return resourceTetrationAgentConfigProfileRead(d, meta)
+}
+
+func resourceTetrationAgentConfigProfileUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(providerMeta).Client
+	err := updateAgentConfigProfile(client, d.Id(), agentConfigProfileFromTerraform(d))
+	if err != nil {
+		return err
+	}
+	return resourceTetrationAgentConfigProfileRead(d, meta)
+}
+
+func resourceTetrationAgentConfigProfileRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(providerMeta).Client
+	profile, err := describeAgentConfigProfile(client, d.Id())
+	if err != nil {
+		return err
+	}
+	d.Set("name", profile.Name)
+	d.Set("root_app_scope_id", profile.RootAppScopeId)
+	d.Set("enforcement_enabled", !profile.EnforcementDisabled)
+	d.Set("preserve_rules", profile.PreserveRules)
+	d.Set("cpu_limit_percent", profile.CpuQuotaPercent)
+	d.Set("memory_limit_mb", profile.MaxRssLimit)
+	d.Set("data_plane_enabled", !profile.DataPlaneDisabled)
+	d.Set("forensics_enabled", profile.EnableForensics)
+	d.Set("process_tracking_enabled", profile.EnablePidLookup)
+	d.Set("auto_upgrade_enabled", !profile.AutoUpgradeOptOut)
+	return nil
+}
+
+func resourceTetrationAgentConfigProfileDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(providerMeta).Client
+	return deleteAgentConfigProfile(client, d.Id())
+}
@@ -0,0 +1,231 @@
+package tetration
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	client "github.com/tetration-exchange/terraform-go-sdk"
+)
+
+const (
+	UpgradedAgentStatus = "upgraded"
+	PendingAgentStatus  = "pending"
+	FailedAgentStatus   = "failed"
+	// Interval between checks of the versions of upgrading agents.
+	AgentUpgradePollInterval = 30 * time.Second
+)
+
+func resourceTetrationAgentUpgrade() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceTetrationAgentUpgradeCreate,
+		Read:   resourceTetrationAgentUpgradeRead,
+		Delete: resourceTetrationAgentUpgradeDelete,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+		},
+
+		SchemaVersion: 1,
+
+		Schema: map[string]*schema.Schema{
+			"filter_id": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"agent_ids"},
+				Description:   "(Optional) ID of the inventory filter matching the workloads whose agents are upgraded. Required unless agent_ids is specified.",
+			},
+			"agent_ids": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				ConflictsWith: []string{"filter_id"},
+				Description:   "(Optional) UUIDs of the agents to upgrade, or of the agents matched by filter_id. Required unless filter_id is specified.",
+			},
+			"version": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Software version to upgrade the agents to.",
+			},
+			"wait_for_completion": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				ForceNew:    true,
+				Description: "(Optional) Wait until all agents run the requested version, up to the create timeout. Default value is true.",
+			},
+			"agent_status": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Upgrade status of each agent.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"uuid": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Unique identifier of the agent.",
+						},
+						"hostname": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Hostname of the workload of the agent.",
+						},
+						"version": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Current software version of the agent.",
+						},
+						"status": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: fmt.Sprintf("Upgrade status of the agent, one of [%s, %s, %s].", UpgradedAgentStatus, PendingAgentStatus, FailedAgentStatus),
+						},
+						"error": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Error of requesting the upgrade of the agent, if it failed.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// upgradeAgent requests the upgrade of an agent to the given version.
+func upgradeAgent(apiClient client.Client, agentUuid string, version string) error {
+	query := url.Values{"sensor_version": {version}}
+	return doRequest(apiClient, http.MethodPost, fmt.Sprintf("/sensors/%s/upgrade?%s", agentUuid, query.Encode()), nil, nil)
+}
+
+// agentUpgradeStatuses returns the upgrade status of the agents with the given
+// uuids as terraform objects, along with the number of agents not yet upgraded.
+// Agents whose upgrade request failed keep their failed status.
+func agentUpgradeStatuses(agents []agent, uuids []string, version string, failures map[string]string) ([]interface{}, int) {
+	agentsByUuid := make(map[string]agent)
+	for _, a := range agents {
+		agentsByUuid[a.Uuid] = a
+	}
+	tfStatuses := make([]interface{}, 0, len(uuids))
+	pending := 0
+	for _, uuid := range uuids {
+		a := agentsByUuid[uuid]
+		status := PendingAgentStatus
+		if failures[uuid] != "" {
+			status = FailedAgentStatus
+		} else if a.CurrentSwVersion == version {
+			status = UpgradedAgentStatus
+		} else {
+			pending++
+		}
+		tfStatuses = append(tfStatuses, terraformObject{
+			"uuid":     uuid,
+			"hostname": a.HostName,
+			"version":  a.CurrentSwVersion,
+			"status":   status,
+			"error":    failures[uuid],
+		})
+	}
+	return tfStatuses, pending
+}
+
+func resourceTetrationAgentUpgradeCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(providerMeta).Client
+	version := d.Get("version").(string)
+	var uuids []string
+	if filterId := d.Get("filter_id").(string); filterId != "" {
+		uuidSet, err := agentUuidsMatchingFilter(client, filterId)
+		if err != nil {
+			return err
+		}
+		for uuid := range uuidSet {
+			uuids = append(uuids, uuid)
+		}
+		sort.Strings(uuids)
+	} else {
+		uuids = stringsFromTerraform(d.Get("agent_ids").([]interface{}))
+	}
+	if len(uuids) == 0 {
+		return errors.New("No agents to upgrade, one of filter_id or agent_ids must match at least one agent")
+	}
+	agents, err := listAgents(client)
+	if err != nil {
+		return err
+	}
+	currentVersions := make(map[string]string)
+	for _, a := range agents {
+		currentVersions[a.Uuid] = a.CurrentSwVersion
+	}
+	failures := make(map[string]string)
+	for _, uuid := range uuids {
+		if currentVersions[uuid] == version {
+			continue
+		}
+		if err := upgradeAgent(client, uuid, version); err != nil {
+			failures[uuid] = err.Error()
+		}
+	}
+	d.SetId(fmt.Sprintf("%s:%x", version, sha256.Sum256([]byte(strings.Join(uuids, ",")))))
+	d.Set("agent_ids", uuids)
+	tfStatuses, pending := agentUpgradeStatuses(agents, uuids, version, failures)
+	d.Set("agent_status", tfStatuses)
+	if d.Get("wait_for_completion").(bool) && pending > 0 {
+		err = resource.Retry(d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
+			time.Sleep(AgentUpgradePollInterval)
+			agents, err := listAgents(client)
+			if err != nil {
+				return resource.NonRetryableError(err)
+			}
+			tfStatuses, pending = agentUpgradeStatuses(agents, uuids, version, failures)
+			d.Set("agent_status", tfStatuses)
+			if pending > 0 {
+				return resource.RetryableError(fmt.Errorf("%d of %d agents are not yet upgraded to version %s", pending, len(uuids), version))
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("Failed to request the upgrade of %d of %d agents to version %s, see agent_status for details", len(failures), len(uuids), version)
+	}
+	return nil
+}
+
+func resourceTetrationAgentUpgradeRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(providerMeta).Client
+	agents, err := listAgents(client)
+	if err != nil {
+		return err
+	}
+	failures := make(map[string]string)
+	for _, tfStatus := range d.Get("agent_status").([]interface{}) {
+		tf := tfStatus.(terraformObject)
+		if tf["status"].(string) == FailedAgentStatus {
+			failures[tf["uuid"].(string)] = tf["error"].(string)
+		}
+	}
+	tfStatuses, _ := agentUpgradeStatuses(agents, stringsFromTerraform(d.Get("agent_ids").([]interface{})), d.Get("version").(string), failures)
+	d.Set("agent_status", tfStatuses)
+	return nil
+}
+
+// resourceTetrationAgentUpgradeDelete only removes the upgrade from state,
+// agents are not downgraded.
+func resourceTetrationAgentUpgradeDelete(d *schema.ResourceData, meta interface{}) error {
+	return nil
+}
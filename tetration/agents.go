@@ -0,0 +1,299 @@
+package tetration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+	client "github.com/tetration-exchange/terraform-go-sdk"
+)
+
+const (
+	ActiveAgentStatus   = "active"
+	InactiveAgentStatus = "inactive"
+	// Maximum number of agents to request per page of agent results.
+	AgentsPageSize = 1000
+	// Maximum number of agents retrieved when listing agents.
+	MaxAgents = 100000
+)
+
+var (
+	ValidAgentStatuses = []string{ActiveAgentStatus, InactiveAgentStatus}
+)
+
+func dataSourceTetrationAgents() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceTetrationAgentsRead,
+
+		Schema: map[string]*schema.Schema{
+			"scope_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "(Optional) ID of a scope to only return the agents of the workloads in.",
+			},
+			"hostname_regex": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.ValidateRegexp,
+				Description:  "(Optional) Regular expression to only return the agents of matching hostnames.",
+			},
+			"status": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice(ValidAgentStatuses, false),
+				Description:  fmt.Sprintf("(Optional) Only return agents with the given status, one of [%s].", strings.Join(ValidAgentStatuses, ", ")),
+			},
+			"inactive_after_minutes": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      60,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "(Optional) Minutes since the last check-in after which an agent is considered inactive. Default value is 60.",
+			},
+			"agent": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Agents matching the filters.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"uuid": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Unique identifier of the agent.",
+						},
+						"hostname": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Hostname of the workload of the agent.",
+						},
+						"ips": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+							Description: "IP addresses of the interfaces of the workload.",
+						},
+						"version": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Software version of the agent.",
+						},
+						"platform": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Operating system platform of the workload.",
+						},
+						"agent_type": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Type of the agent, such as ENFORCER or SENSOR.",
+						},
+						"enforcement_enabled": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether the agent enforces policies.",
+						},
+						"last_check_in": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "RFC 3339 timestamp of the last time the agent fetched its configuration.",
+						},
+						"status": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: fmt.Sprintf("Status of the agent, one of [%s].", strings.Join(ValidAgentStatuses, ", ")),
+						},
+						"config_profile_id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "ID of the agent config profile applied to the agent.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// agentInterface describes a network interface of the workload of an agent.
+type agentInterface struct {
+	Ip string `json:"ip"`
+}
+
+// agent describes a software agent installed on a workload.
+type agent struct {
+	Uuid              string           `json:"uuid"`
+	HostName          string           `json:"host_name"`
+	Interfaces        []agentInterface `json:"interfaces"`
+	CurrentSwVersion  string           `json:"current_sw_version"`
+	Platform          string           `json:"platform"`
+	AgentType         string           `json:"agent_type"`
+	EnableEnforcement bool             `json:"enable_enforcement"`
+	// Unix timestamp of the last time the agent fetched its configuration.
+	LastConfigFetchAt int64  `json:"last_config_fetch_at"`
+	ConfigProfileId   string `json:"inventory_config_profile_id"`
+}
+
+// agentsResponse wraps a page of agents.
+type agentsResponse struct {
+	// Offset to request the next page of results with, empty for the last page.
+	Offset  string  `json:"offset"`
+	Results []agent `json:"results"`
+}
+
+// listAgents retrieves all pages of agents, up to MaxAgents agents, returning
+// the agents.
+func listAgents(apiClient client.Client) ([]agent, error) {
+	var agents []agent
+	offset := ""
+	for {
+		query := url.Values{"limit": {fmt.Sprint(AgentsPageSize)}}
+		if offset != "" {
+			query.Set("offset", offset)
+		}
+		var page agentsResponse
+		err := doRequest(apiClient, http.MethodGet, "/sensors?"+query.Encode(), nil, &page)
+		if err != nil {
+			return agents, err
+		}
+		agents = append(agents, page.Results...)
+		if page.Offset == "" || len(page.Results) == 0 {
+			return agents, nil
+		}
+		if len(agents) >= MaxAgents {
+			return agents, fmt.Errorf("More than %d agents are registered, listing them was stopped", MaxAgents)
+		}
+		if page.Offset == offset {
+			return agents, fmt.Errorf("Listing agents returned the same offset %q twice", page.Offset)
+		}
+		offset = page.Offset
+	}
+}
+
+// agentUuidsInScope returns the set of uuids of the agents of the workloads in
+// the scope with the given id.
+func agentUuidsInScope(apiClient client.Client, scopeId string) (map[string]bool, error) {
+	scope, err := apiClient.DescribeScope(scopeId)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to describe scope %s: %s", scopeId, err)
+	}
+	query, err := json.Marshal(inventoryQueryFromScopeQuery(scope.Query))
+	if err != nil {
+		return nil, err
+	}
+	return agentUuidsMatching(apiClient, scope.Name, query)
+}
+
+// agentUuidsMatchingFilter returns the set of uuids of the agents of the
+// workloads matching the inventory filter with the given id.
+func agentUuidsMatchingFilter(apiClient client.Client, filterId string) (map[string]bool, error) {
+	filter, err := apiClient.DescribeFilter(filterId)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to describe filter %s: %s", filterId, err)
+	}
+	scope, err := apiClient.DescribeScope(filter.AppScopeId)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to describe scope %s: %s", filter.AppScopeId, err)
+	}
+	return agentUuidsMatching(apiClient, scope.Name, filter.QueryJSON)
+}
+
+// agentUuidsMatching returns the set of uuids of the agents of the workloads in
+// the named scope matching the inventory query.
+func agentUuidsMatching(apiClient client.Client, scopeName string, query json.RawMessage) (map[string]bool, error) {
+	items, err := searchInventory(apiClient, inventorySearchRequest{
+		Filter:     query,
+		ScopeName:  scopeName,
+		Dimensions: []string{"host_uuid"},
+	}, 0)
+	if err != nil {
+		return nil, err
+	}
+	uuids := make(map[string]bool)
+	for _, item := range items {
+		if uuid, ok := item["host_uuid"].(string); ok && uuid != "" {
+			uuids[uuid] = true
+		}
+	}
+	return uuids, nil
+}
+
+// status returns whether the agent checked in within the given duration.
+func (a agent) status(inactiveAfter time.Duration, now time.Time) string {
+	if now.Sub(time.Unix(a.LastConfigFetchAt, 0)) > inactiveAfter {
+		return InactiveAgentStatus
+	}
+	return ActiveAgentStatus
+}
+
+// agentToTerraform returns an agent as a terraform agent object.
+func agentToTerraform(a agent, status string) terraformObject {
+	ips := make([]interface{}, 0, len(a.Interfaces))
+	for _, agentInterface := range a.Interfaces {
+		ips = append(ips, agentInterface.Ip)
+	}
+	lastCheckIn := ""
+	if a.LastConfigFetchAt > 0 {
+		lastCheckIn = time.Unix(a.LastConfigFetchAt, 0).UTC().Format(time.RFC3339)
+	}
+	return terraformObject{
+		"uuid":                a.Uuid,
+		"hostname":            a.HostName,
+		"ips":                 ips,
+		"version":             a.CurrentSwVersion,
+		"platform":            a.Platform,
+		"agent_type":          a.AgentType,
+		"enforcement_enabled": a.EnableEnforcement,
+		"last_check_in":       lastCheckIn,
+		"status":              status,
+		"config_profile_id":   a.ConfigProfileId,
+	}
+}
+
+func dataSourceTetrationAgentsRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(providerMeta).Client
+	agents, err := listAgents(client)
+	if err != nil {
+		return err
+	}
+	scopeId := d.Get("scope_id").(string)
+	var scopeUuids map[string]bool
+	if scopeId != "" {
+		scopeUuids, err = agentUuidsInScope(client, scopeId)
+		if err != nil {
+			return err
+		}
+	}
+	hostnameRegex := d.Get("hostname_regex").(string)
+	hostnamePattern, err := regexp.Compile(hostnameRegex)
+	if err != nil {
+		return err
+	}
+	statusFilter := d.Get("status").(string)
+	inactiveAfter := time.Duration(d.Get("inactive_after_minutes").(int)) * time.Minute
+	now := time.Now()
+	tfAgents := []interface{}{}
+	for _, a := range agents {
+		if scopeUuids != nil && !scopeUuids[a.Uuid] {
+			continue
+		}
+		if !hostnamePattern.MatchString(a.HostName) {
+			continue
+		}
+		status := a.status(inactiveAfter, now)
+		if statusFilter != "" && status != statusFilter {
+			continue
+		}
+		tfAgents = append(tfAgents, agentToTerraform(a, status))
+	}
+	d.Set("agent", tfAgents)
+	d.SetId(fmt.Sprintf("%s:%s:%s", scopeId, hostnameRegex, statusFilter))
+	return nil
+}
@@ -0,0 +1,75 @@
+package tetration
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	tetration "github.com/tetration-exchange/terraform-go-sdk"
+)
+
+// testAgentsClient returns a client for a server serving the given
+// pages of agents, keyed by the offset they are requested with.
+func testAgentsClient(t *testing.T, pages map[string]agentsResponse) (tetration.Client, func()) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, ok := pages[r.URL.Query().Get("offset")]
+		if !ok || !strings.HasSuffix(r.URL.Path, "/sensors") {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(page)
+	}))
+	apiClient, err := tetration.New(tetration.Config{
+		APIKey:    "key",
+		APISecret: strings.Repeat("s", 40),
+		APIURL:    server.URL,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	return apiClient, server.Close
+}
+
+func TestListAgents(t *testing.T) {
+	apiClient, closeServer := testAgentsClient(t, map[string]agentsResponse{
+		"":  {Offset: "2", Results: []agent{{Uuid: "a"}, {Uuid: "b"}}},
+		"2": {Results: []agent{{Uuid: "c"}}},
+	})
+	defer closeServer()
+	agents, err := listAgents(apiClient)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if len(agents) != 3 || agents[2].Uuid != "c" {
+		t.Errorf("Unexpected agents %v", agents)
+	}
+}
+
+func TestListAgentsStopsOnRepeatedOffset(t *testing.T) {
+	apiClient, closeServer := testAgentsClient(t, map[string]agentsResponse{
+		"":  {Offset: "2", Results: []agent{{Uuid: "a"}}},
+		"2": {Offset: "2", Results: []agent{{Uuid: "b"}}},
+	})
+	defer closeServer()
+	if _, err := listAgents(apiClient); err == nil {
+		t.Errorf("Expected an error for a repeated offset")
+	}
+}
+
+func TestStaleAgents(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	agents := []agent{
+		{Uuid: "stale", LastConfigFetchAt: now.Add(-48 * time.Hour).Unix()},
+		{Uuid: "active", LastConfigFetchAt: now.Add(-time.Hour).Unix()},
+		{Uuid: "unknown"},
+		{Uuid: "other", LastConfigFetchAt: now.Add(-48 * time.Hour).Unix()},
+	}
+	scopeUuids := map[string]bool{"stale": true, "active": true, "unknown": true}
+	stale := staleAgents(agents, scopeUuids, 24*time.Hour, now)
+	if len(stale) != 1 || stale[0].Uuid != "stale" {
+		t.Errorf("Expected only the stale agent, got %v", stale)
+	}
+}
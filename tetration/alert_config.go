@@ -0,0 +1,289 @@
+package tetration
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+	client "github.com/tetration-exchange/terraform-go-sdk"
+)
+
+const (
+	EnforcementAlertType = "enforcement"
+	ComplianceAlertType  = "compliance"
+	SensorAlertType      = "sensor"
+	ForensicAlertType    = "forensic"
+)
+
+var (
+	ValidAlertTypes      = []string{EnforcementAlertType, ComplianceAlertType, SensorAlertType, ForensicAlertType}
+	ValidAlertSeverities = []string{"LOW", "MEDIUM", "HIGH", "CRITICAL", "IMMEDIATE_ACTION"}
+	ValidSyslogProtocols = []string{"UDP", "TCP"}
+)
+
+func resourceTetrationAlertConfig() *schema.Resource {
+	return &schema.Resource{
+		Create:        resourceTetrationAlertConfigCreate,
+		Update:        resourceTetrationAlertConfigUpdate,
+		Read:          resourceTetrationAlertConfigRead,
+		Delete:        resourceTetrationAlertConfigDelete,
+		CustomizeDiff: resourceTetrationAlertConfigCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		SchemaVersion: 1,
+
+		Schema: map[string]*schema.Schema{
+			"root_app_scope_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "ID of the root scope the alert config belongs to.",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "User-specified name for the alert config.",
+			},
+			"alert_types": {
+				Type:     schema.TypeList,
+				Required: true,
+				MinItems: 1,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringInSlice(ValidAlertTypes, false),
+				},
+				Description: fmt.Sprintf("Types of the alerts to route, any of [%s].", strings.Join(ValidAlertTypes, ", ")),
+			},
+			"min_severity": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      ValidAlertSeverities[0],
+				ValidateFunc: validation.StringInSlice(ValidAlertSeverities, false),
+				Description:  fmt.Sprintf("(Optional) Minimum severity of the alerts to route, one of [%s]. Default value is %s.", strings.Join(ValidAlertSeverities, ", "), ValidAlertSeverities[0]),
+			},
+			"scope_ids": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "(Optional) IDs of the scopes to route the alerts of. Defaults to all scopes of the root scope.",
+			},
+			"datatap_ids": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "(Optional) IDs of the data taps to publish the alerts to.",
+			},
+			"syslog": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "(Optional) Syslog servers to send the alerts to.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"server": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "Hostname or IP address of the syslog server.",
+						},
+						"port": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Default:      514,
+							ValidateFunc: validation.IntBetween(1, 65535),
+							Description:  "(Optional) Port of the syslog server. Default value is 514.",
+						},
+						"protocol": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      ValidSyslogProtocols[0],
+							ValidateFunc: validation.StringInSlice(ValidSyslogProtocols, false),
+							Description:  fmt.Sprintf("(Optional) Transport protocol, one of [%s]. Default value is %s.", strings.Join(ValidSyslogProtocols, ", "), ValidSyslogProtocols[0]),
+						},
+					},
+				},
+			},
+			"email_addresses": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "(Optional) Email addresses to send the alerts to.",
+			},
+			"destination_status": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Connectivity of each destination of the alerts, as of the last refresh.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"destination": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Data tap id, syslog server or email address of the destination.",
+						},
+						"status": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Status of the connection to the destination.",
+						},
+						"error": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Error of the last delivery of alerts to the destination, empty if delivered.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// alertSyslogServer describes a syslog server alerts are sent to.
+type alertSyslogServer struct {
+	Server   string `json:"server"`
+	Port     int    `json:"port"`
+	Protocol string `json:"protocol"`
+}
+
+// alertDestinationStatus describes the connectivity of a destination of alerts.
+type alertDestinationStatus struct {
+	Destination   string `json:"destination"`
+	Status        string `json:"status"`
+	FailureReason string `json:"failure_reason"`
+}
+
+// alertConfig describes the routing of alerts to data taps, syslog servers and email addresses.
+type alertConfig struct {
+	Id                string                   `json:"id,omitempty"`
+	RootAppScopeId    string                   `json:"root_app_scope_id,omitempty"`
+	Name              string                   `json:"name"`
+	AlertTypes        []string                 `json:"alert_types"`
+	MinSeverity       string                   `json:"min_severity"`
+	ScopeIds          []string                 `json:"scope_ids"`
+	DatatapIds        []string                 `json:"datatap_ids"`
+	SyslogServers     []alertSyslogServer      `json:"syslog_servers"`
+	EmailAddresses    []string                 `json:"email_addresses"`
+	DestinationStatus []alertDestinationStatus `json:"destination_status,omitempty"`
+}
+
+// createAlertConfig creates an alert config, returning the created config.
+func createAlertConfig(apiClient client.Client, params alertConfig) (alertConfig, error) {
+	var created alertConfig
+	err := doRequest(apiClient, http.MethodPost, "/alert_configs", params, &created)
+	return created, err
+}
+
+func describeAlertConfig(apiClient client.Client, alertConfigId string) (alertConfig, error) {
+	var described alertConfig
+	err := doRequest(apiClient, http.MethodGet, fmt.Sprintf("/alert_configs/%s", alertConfigId), nil, &described)
+	return described, err
+}
+
+func updateAlertConfig(apiClient client.Client, alertConfigId string, params alertConfig) error {
+	return doRequest(apiClient, http.MethodPut, fmt.Sprintf("/alert_configs/%s", alertConfigId), params, nil)
+}
+
+func deleteAlertConfig(apiClient client.Client, alertConfigId string) error {
+	return doRequest(apiClient, http.MethodDelete, fmt.Sprintf("/alert_configs/%s", alertConfigId), nil, nil)
+}
+
+// alertConfigFromTerraform returns the alert config configured by the resource.
+func alertConfigFromTerraform(d *schema.ResourceData) alertConfig {
+	tfSyslogServers := d.Get("syslog").([]interface{})
+	syslogServers := make([]alertSyslogServer, 0, len(tfSyslogServers))
+	for _, tfSyslogServer := range tfSyslogServers {
+		tf := tfSyslogServer.(terraformObject)
+		syslogServers = append(syslogServers, alertSyslogServer{
+			Server:   tf["server"].(string),
+			Port:     tf["port"].(int),
+			Protocol: tf["protocol"].(string),
+		})
+	}
+	return alertConfig{
+		RootAppScopeId: d.Get("root_app_scope_id").(string),
+		Name:           d.Get("name").(string),
+		AlertTypes:     stringsFromTerraform(d.Get("alert_types").([]interface{})),
+		MinSeverity:    d.Get("min_severity").(string),
+		ScopeIds:       stringsFromTerraform(d.Get("scope_ids").([]interface{})),
+		DatatapIds:     stringsFromTerraform(d.Get("datatap_ids").([]interface{})),
+		SyslogServers:  syslogServers,
+		EmailAddresses: stringsFromTerraform(d.Get("email_addresses").([]interface{})),
+	}
+}
+
+func resourceTetrationAlertConfigCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(providerMeta).Client
+	created, err := createAlertConfig(client, alertConfigFromTerraform(d))
+	if err != nil {
+		return err
+	}
+	d.SetId(created.Id)
+	return resourceTetrationAlertConfigRead(d, meta)
+}
+
+func resourceTetrationAlertConfigUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(providerMeta).Client
+	err := updateAlertConfig(client, d.Id(), alertConfigFromTerraform(d))
+	if err != nil {
+		return err
+	}
+	return resourceTetrationAlertConfigRead(d, meta)
+}
+
+func resourceTetrationAlertConfigRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(providerMeta).Client
+	described, err := describeAlertConfig(client, d.Id())
+	if err != nil {
+		return err
+	}
+	tfSyslogServers := make([]interface{}, 0, len(described.SyslogServers))
+	for _, syslogServer := range described.SyslogServers {
+		tfSyslogServers = append(tfSyslogServers, terraformObject{
+			"server":   syslogServer.Server,
+			"port":     syslogServer.Port,
+			"protocol": syslogServer.Protocol,
+		})
+	}
+	tfStatuses := make([]interface{}, 0, len(described.DestinationStatus))
+	for _, status := range described.DestinationStatus {
+		tfStatuses = append(tfStatuses, terraformObject{
+			"destination": status.Destination,
+			"status":      status.Status,
+			"error":       status.FailureReason,
+		})
+	}
+	d.Set("root_app_scope_id", described.RootAppScopeId)
+	d.Set("name", described.Name)
+	d.Set("alert_types", described.AlertTypes)
+	d.Set("min_severity", described.MinSeverity)
+	d.Set("scope_ids", described.ScopeIds)
+	d.Set("datatap_ids", described.DatatapIds)
+	d.Set("syslog", tfSyslogServers)
+	d.Set("email_addresses", described.EmailAddresses)
+	d.Set("destination_status", tfStatuses)
+	return nil
+}
+
+func resourceTetrationAlertConfigDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(providerMeta).Client
+	return deleteAlertConfig(client, d.Id())
+}
+
+// resourceTetrationAlertConfigCustomizeDiff validates that alerts are routed to at least one destination.
+func resourceTetrationAlertConfigCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	destinations := []string{"datatap_ids", "syslog", "email_addresses"}
+	for _, destination := range destinations {
+		if !d.NewValueKnown(destination) || len(d.Get(destination).([]interface{})) > 0 {
+			return nil
+		}
+	}
+	return errors.New("At least one of datatap_ids, syslog or email_addresses must be specified")
+}
@@ -0,0 +1,258 @@
+package tetration
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	client "github.com/tetration-exchange/terraform-go-sdk"
+)
+
+const (
+	// Maximum number of user annotation columns Tetration enables per tenant.
+	MaxEnabledAnnotationColumns = 32
+)
+
+func resourceTetrationAnnotationSchema() *schema.Resource {
+	return &schema.Resource{
+		Create:        resourceTetrationAnnotationSchemaCreate,
+		Update:        resourceTetrationAnnotationSchemaCreate,
+		Read:          resourceTetrationAnnotationSchemaRead,
+		Delete:        resourceTetrationAnnotationSchemaDelete,
+		CustomizeDiff: resourceTetrationAnnotationSchemaCustomizeDiff,
+
+		SchemaVersion: 1,
+
+		Schema: map[string]*schema.Schema{
+			"tenant_name":       tenantNameSchema(true),
+			"root_app_scope_id": rootAppScopeIdSchema(true),
+			"key": {
+				Type:        schema.TypeList,
+				Required:    true,
+				Description: "User annotation column (label key) of the tenant.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "Name of the annotation key.",
+						},
+						"enabled": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     true,
+							Description: fmt.Sprintf("(Optional) Whether the key is enabled for tagging flows and inventory items, at most %d keys can be enabled. Default value is true.", MaxEnabledAnnotationColumns),
+						},
+					},
+				},
+			},
+			"enabled_keys": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Names of the enabled annotation keys, for validating the attributes of tetration_tag resources.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+	}
+}
+
+// annotationColumn describes a user annotation column of a tenant.
+type annotationColumn struct {
+	Name    string `json:"name"`
+	Enabled bool   `json:"enabled"`
+}
+
+// updateAnnotationColumnsRequest wraps parameters for making a request
+// to declare the user annotation columns of a tenant.
+type updateAnnotationColumnsRequest struct {
+	Columns []annotationColumn `json:"columns"`
+}
+
+func listAnnotationColumns(apiClient client.Client, tenantName string) ([]annotationColumn, error) {
+	var columns []annotationColumn
+	err := doRequest(apiClient, http.MethodGet, fmt.Sprintf("/assets/cmdb/annotations/%s", tenantName), nil, &columns)
+	return columns, err
+}
+
+// updateAnnotationColumns creates, enables or disables the given user
+// annotation columns of the tenant, leaving other columns untouched.
+func updateAnnotationColumns(apiClient client.Client, tenantName string, columns []annotationColumn) error {
+	params := updateAnnotationColumnsRequest{Columns: columns}
+	return doRequest(apiClient, http.MethodPut, fmt.Sprintf("/assets/cmdb/annotations/%s", tenantName), params, nil)
+}
+
+// annotationColumnsFromTerraform returns the annotation columns declared by key
+// blocks, failing on duplicate keys or too many enabled keys.
+func annotationColumnsFromTerraform(tfKeys []interface{}) ([]annotationColumn, error) {
+	var columns []annotationColumn
+	names := make(map[string]bool)
+	enabledCount := 0
+	for _, tfKey := range tfKeys {
+		tf := tfKey.(terraformObject)
+		column := annotationColumn{
+			Name:    tf["name"].(string),
+			Enabled: tf["enabled"].(bool),
+		}
+		if names[column.Name] {
+			return nil, fmt.Errorf("Annotation key %s is declared more than once", column.Name)
+		}
+		names[column.Name] = true
+		if column.Enabled {
+			enabledCount++
+		}
+		columns = append(columns, column)
+	}
+	if enabledCount > MaxEnabledAnnotationColumns {
+		return nil, fmt.Errorf("%d annotation keys are enabled, at most %d keys can be enabled", enabledCount, MaxEnabledAnnotationColumns)
+	}
+	return columns, nil
+}
+
+// enabledAnnotationKeys returns the names of the enabled columns.
+func enabledAnnotationKeys(columns []annotationColumn) []string {
+	keys := []string{}
+	for _, column := range columns {
+		if column.Enabled {
+			keys = append(keys, column.Name)
+		}
+	}
+	return keys
+}
+
+// validateAnnotationKeys returns an error naming the attribute keys that are
+// not among the enabled annotation keys.
+func validateAnnotationKeys(attributes map[string]interface{}, enabledKeys []string) error {
+	enabled := make(map[string]bool)
+	for _, key := range enabledKeys {
+		enabled[key] = true
+	}
+	var unknownKeys []string
+	for key := range attributes {
+		if !enabled[key] {
+			unknownKeys = append(unknownKeys, key)
+		}
+	}
+	if len(unknownKeys) == 0 {
+		return nil
+	}
+	sort.Strings(unknownKeys)
+	return fmt.Errorf("Annotation keys [%s] are not enabled in the annotation schema, enabled keys are [%s]",
+		strings.Join(unknownKeys, ", "), strings.Join(enabledKeys, ", "))
+}
+
+func resourceTetrationAnnotationSchemaCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(providerMeta).Client
+	tenantName := d.Get("tenant_name").(string)
+	if d.Id() == "" {
+		var err error
+		tenantName, err = resolveTenant(meta.(providerMeta), tenantName, d.Get("root_app_scope_id").(string))
+		if err != nil {
+			return err
+		}
+	}
+	columns, err := annotationColumnsFromTerraform(d.Get("key").([]interface{}))
+	if err != nil {
+		return err
+	}
+	// Disable keys that were removed from the configuration, without
+	// deleting the annotations uploaded for them
+	if d.HasChange("key") {
+		declared := make(map[string]bool)
+		for _, column := range columns {
+			declared[column.Name] = true
+		}
+		previous, _ := d.GetChange("key")
+		for _, tfKey := range previous.([]interface{}) {
+			name := tfKey.(terraformObject)["name"].(string)
+			if !declared[name] {
+				columns = append(columns, annotationColumn{Name: name, Enabled: false})
+			}
+		}
+	}
+	err = updateAnnotationColumns(client, tenantName, columns)
+	if err != nil {
+		return err
+	}
+	d.Set("tenant_name", tenantName)
+	d.SetId(tenantName)
+	return resourceTetrationAnnotationSchemaRead(d, meta)
+}
+
+func resourceTetrationAnnotationSchemaRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(providerMeta).Client
+	columns, err := listAnnotationColumns(client, d.Id())
+	if err != nil {
+		return err
+	}
+	columnsByName := make(map[string]annotationColumn)
+	for _, column := range columns {
+		columnsByName[column.Name] = column
+	}
+	// Only the declared keys are managed, keys missing from
+	// Tetration are dropped so they are created again
+	var tfKeys []interface{}
+	var declaredColumns []annotationColumn
+	for _, tfKey := range d.Get("key").([]interface{}) {
+		column, ok := columnsByName[tfKey.(terraformObject)["name"].(string)]
+		if !ok {
+			continue
+		}
+		declaredColumns = append(declaredColumns, column)
+		tfKeys = append(tfKeys, terraformObject{
+			"name":    column.Name,
+			"enabled": column.Enabled,
+		})
+	}
+	d.Set("tenant_name", d.Id())
+	d.Set("key", tfKeys)
+	d.Set("enabled_keys", enabledAnnotationKeys(declaredColumns))
+	return nil
+}
+
+func resourceTetrationAnnotationSchemaDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(providerMeta).Client
+	columns, err := annotationColumnsFromTerraform(d.Get("key").([]interface{}))
+	if err != nil {
+		return err
+	}
+	// Disable rather than delete the columns, deleting a
+	// column would delete the annotations uploaded for it
+	for index := range columns {
+		columns[index].Enabled = false
+	}
+	return updateAnnotationColumns(client, d.Id(), columns)
+}
+
+func resourceTetrationAnnotationSchemaCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("key") {
+		return d.SetNewComputed("enabled_keys")
+	}
+	tfKeys := d.Get("key").([]interface{})
+	for _, tfKey := range tfKeys {
+		if tfKey == nil {
+			return errors.New("Every annotation key must have a name")
+		}
+	}
+	columns, err := annotationColumnsFromTerraform(tfKeys)
+	if err != nil {
+		return err
+	}
+	if d.HasChange("key") {
+		return d.SetNew("enabled_keys", enabledAnnotationKeys(columns))
+	}
+	return nil
+}
+
+// stringsFromTerraform converts a terraform list of strings to a string slice.
+func stringsFromTerraform(tfStrings []interface{}) []string {
+	values := make([]string, 0, len(tfStrings))
+	for _, value := range tfStrings {
+		values = append(values, value.(string))
+	}
+	return values
+}
@@ -0,0 +1,457 @@
+package tetration
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+	client "github.com/tetration-exchange/terraform-go-sdk"
+)
+
+const (
+	AnnotationsIpColumn = "IP"
+	// Form field of an annotation upload selecting how rows are applied.
+	AnnotationsOperationField  = "X-Tetration-Oper"
+	DeleteAnnotationsOperation = "delete"
+)
+
+var (
+	ValidAnnotationsOperations = []string{"add", "merge", "overwrite", DeleteAnnotationsOperation}
+)
+
+func resourceTetrationAnnotations() *schema.Resource {
+	return &schema.Resource{
+		Create:        resourceTetrationAnnotationsCreate,
+		Update:        resourceTetrationAnnotationsUpdate,
+		Read:          resourceTetrationAnnotationsRead,
+		Delete:        resourceTetrationAnnotationsDelete,
+		CustomizeDiff: resourceTetrationAnnotationsCustomizeDiff,
+
+		SchemaVersion: 1,
+
+		Schema: map[string]*schema.Schema{
+			"tenant_name":       tenantNameSchema(true),
+			"root_app_scope_id": rootAppScopeIdSchema(true),
+			"operation": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "merge",
+				ValidateFunc: validation.StringInSlice(ValidAnnotationsOperations, false),
+				Description:  fmt.Sprintf("(Optional) How the rows are applied to existing annotations, one of [%s]. Default value is merge.", strings.Join(ValidAnnotationsOperations, ", ")),
+			},
+			"row": {
+				Type:          schema.TypeList,
+				Optional:      true,
+				ConflictsWith: []string{"csv", "csv_file"},
+				Description:   "(Optional) Annotations for an IP address or subnet.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"ip": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validateTagIp,
+							Description:  "IPv4/IPv6 address or subnet.",
+						},
+						"attributes": {
+							Type:        schema.TypeMap,
+							Required:    true,
+							Description: "Key/value map for tagging matching flows and inventory items.",
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+					},
+				},
+			},
+			"csv": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"row", "csv_file"},
+				Description:   "(Optional) CSV document of annotations, with a header row whose first column is IP followed by the annotation keys.",
+			},
+			"csv_file": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"row", "csv"},
+				Description:   "(Optional) Path of a CSV file of annotations, in the same format as csv. Changes to the file content are detected through content_hash.",
+			},
+			"fail_on_warnings": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "(Optional) Return an error if Tetration reports rows that could not be applied. Default value is false.",
+			},
+			"content_hash": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Hash of the annotations of the managed IPs as last uploaded or read back from Tetration, used to detect drift.",
+			},
+			"ips": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "IP addresses and subnets whose annotations are managed.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"warnings": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Rows Tetration reported as not applied during the last upload.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+	}
+}
+
+// annotationRow wraps the annotations of a single IP address or subnet.
+type annotationRow struct {
+	Ip         string
+	Attributes map[string]string
+}
+
+// annotationsUploadResponse wraps the result of uploading annotations.
+type annotationsUploadResponse struct {
+	// Rows that could not be applied.
+	Warnings []string `json:"warnings"`
+}
+
+// annotationRowsFromCSV parses annotation rows from a CSV document whose first
+// column is the IP, returning the rows.
+func annotationRowsFromCSV(document []byte) ([]annotationRow, error) {
+	records, err := csv.NewReader(bytes.NewReader(document)).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 || len(records[0]) == 0 || !strings.EqualFold(strings.TrimSpace(records[0][0]), AnnotationsIpColumn) {
+		return nil, fmt.Errorf("The first column of the annotations CSV header must be %s", AnnotationsIpColumn)
+	}
+	header := records[0]
+	var rows []annotationRow
+	for _, record := range records[1:] {
+		row := annotationRow{
+			Ip:         canonicalAnnotationIp(record[0]),
+			Attributes: make(map[string]string),
+		}
+		for index := 1; index < len(header) && index < len(record); index++ {
+			row.Attributes[strings.TrimSpace(header[index])] = record[index]
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// canonicalAnnotationIp returns the canonical form of the IP address or subnet of
+// an annotation row, so that rows compare equal to the rows Tetration returns.
+// Single address subnets such as 10.0.0.1/32 are reduced to the address, and
+// invalid values are returned as is to be reported by validation.
+func canonicalAnnotationIp(value string) string {
+	canonical, err := canonicalTagIp(value)
+	if err != nil {
+		return strings.TrimSpace(value)
+	}
+	_, network, err := net.ParseCIDR(canonical)
+	if err != nil {
+		return canonical
+	}
+	if ones, bits := network.Mask.Size(); ones == bits {
+		return network.IP.String()
+	}
+	return canonical
+}
+
+// annotationRowsToCSV encodes annotation rows as a CSV document with a column
+// for every annotation key, returning the document.
+func annotationRowsToCSV(rows []annotationRow) ([]byte, error) {
+	keys := annotationKeys(rows)
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+	err := writer.Write(append([]string{AnnotationsIpColumn}, keys...))
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		record := []string{row.Ip}
+		for _, key := range keys {
+			record = append(record, row.Attributes[key])
+		}
+		err = writer.Write(record)
+		if err != nil {
+			return nil, err
+		}
+	}
+	writer.Flush()
+	return buffer.Bytes(), writer.Error()
+}
+
+// annotationKeys returns the sorted set of annotation keys used by the rows.
+func annotationKeys(rows []annotationRow) []string {
+	keySet := make(map[string]bool)
+	for _, row := range rows {
+		for key := range row.Attributes {
+			keySet[key] = true
+		}
+	}
+	keys := make([]string, 0, len(keySet))
+	for key := range keySet {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// annotationsHash returns a hash of the non empty annotations of the rows,
+// independent of the order of the rows and their keys.
+func annotationsHash(rows []annotationRow) string {
+	var lines []string
+	for _, row := range rows {
+		var attributes []string
+		for key, value := range row.Attributes {
+			if value != "" {
+				attributes = append(attributes, fmt.Sprintf("%s=%s", key, value))
+			}
+		}
+		if len(attributes) == 0 {
+			continue
+		}
+		sort.Strings(attributes)
+		lines = append(lines, row.Ip+"\t"+strings.Join(attributes, "\t"))
+	}
+	sort.Strings(lines)
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(strings.Join(lines, "\n"))))
+}
+
+// annotationRowsFromTerraform returns the annotation rows configured through
+// the row blocks, csv document or csv file.
+func annotationRowsFromTerraform(tfRows []interface{}, csvDocument string, csvFile string) ([]annotationRow, error) {
+	if csvFile != "" {
+		document, err := ioutil.ReadFile(csvFile)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to read annotations CSV file %s: %s", csvFile, err)
+		}
+		return annotationRowsFromCSV(document)
+	}
+	if csvDocument != "" {
+		return annotationRowsFromCSV([]byte(csvDocument))
+	}
+	var rows []annotationRow
+	for _, tfRow := range tfRows {
+		if tfRow == nil {
+			continue
+		}
+		tf := tfRow.(terraformObject)
+		row := annotationRow{
+			Ip:         canonicalAnnotationIp(tf["ip"].(string)),
+			Attributes: make(map[string]string),
+		}
+		for key, value := range tf["attributes"].(map[string]interface{}) {
+			row.Attributes[key] = value.(string)
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// desiredAnnotationsHash returns the content hash the managed IPs
+// should have in Tetration after applying the rows with the operation.
+func desiredAnnotationsHash(rows []annotationRow, operation string) string {
+	if operation == DeleteAnnotationsOperation {
+		return annotationsHash(nil)
+	}
+	return annotationsHash(rows)
+}
+
+// uploadAnnotations uploads annotation rows to the tenant applying them with
+// the given operation, returning the rows reported as not applied.
+func uploadAnnotations(apiClient client.Client, tenantName string, operation string, rows []annotationRow) ([]string, error) {
+	document, err := annotationRowsToCSV(rows)
+	if err != nil {
+		return nil, err
+	}
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	err = writer.WriteField(AnnotationsOperationField, operation)
+	if err != nil {
+		return nil, err
+	}
+	file, err := writer.CreateFormFile("file", "annotations.csv")
+	if err != nil {
+		return nil, err
+	}
+	_, err = file.Write(document)
+	if err != nil {
+		return nil, err
+	}
+	err = writer.Close()
+	if err != nil {
+		return nil, err
+	}
+	responseBody, err := doRawRequest(apiClient, http.MethodPost, fmt.Sprintf("/assets/cmdb/upload/%s", tenantName), writer.FormDataContentType(), body.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	var response annotationsUploadResponse
+	if len(bytes.TrimSpace(responseBody)) > 0 {
+		err = json.Unmarshal(responseBody, &response)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return response.Warnings, nil
+}
+
+// downloadAnnotations downloads all user uploaded annotations of the tenant,
+// returning the rows.
+func downloadAnnotations(apiClient client.Client, tenantName string) ([]annotationRow, error) {
+	document, err := doRawRequest(apiClient, http.MethodGet, fmt.Sprintf("/assets/cmdb/download/%s", tenantName), "text/csv", nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(bytes.TrimSpace(document)) == 0 {
+		return nil, nil
+	}
+	return annotationRowsFromCSV(document)
+}
+
+// applyAnnotations uploads the configured rows, recording the managed IPs,
+// content hash and warnings in state.
+func applyAnnotations(d *schema.ResourceData, meta interface{}, tenantName string) error {
+	client := meta.(providerMeta).Client
+	rows, err := annotationRowsFromTerraform(d.Get("row").([]interface{}), d.Get("csv").(string), d.Get("csv_file").(string))
+	if err != nil {
+		return err
+	}
+	operation := d.Get("operation").(string)
+	// Rows removed since the last upload no longer belong
+	// to this resource and have their annotations deleted
+	managedIps := make(map[string]bool)
+	for _, row := range rows {
+		managedIps[row.Ip] = true
+	}
+	var removedRows []annotationRow
+	for _, ip := range d.Get("ips").([]interface{}) {
+		if !managedIps[canonicalAnnotationIp(ip.(string))] {
+			removedRows = append(removedRows, annotationRow{Ip: ip.(string)})
+		}
+	}
+	if len(removedRows) > 0 {
+		_, err = uploadAnnotations(client, tenantName, DeleteAnnotationsOperation, removedRows)
+		if err != nil {
+			return err
+		}
+	}
+	warnings, err := uploadAnnotations(client, tenantName, operation, rows)
+	if err != nil {
+		return err
+	}
+	ips := make([]interface{}, 0, len(rows))
+	for _, row := range rows {
+		ips = append(ips, row.Ip)
+	}
+	d.Set("ips", ips)
+	d.Set("warnings", warnings)
+	d.Set("content_hash", desiredAnnotationsHash(rows, operation))
+	if len(warnings) > 0 && d.Get("fail_on_warnings").(bool) {
+		return fmt.Errorf("%d annotation rows for tenant %s were not applied:\n%s", len(warnings), tenantName, strings.Join(warnings, "\n"))
+	}
+	return nil
+}
+
+func resourceTetrationAnnotationsCreate(d *schema.ResourceData, meta interface{}) error {
+	tenantName, err := resolveTenant(meta.(providerMeta), d.Get("tenant_name").(string), d.Get("root_app_scope_id").(string))
+	if err != nil {
+		return err
+	}
+	d.Set("tenant_name", tenantName)
+	// Track the resource even if some rows fail to apply, so their annotations can be retried or destroyed.
+	// The managed rows change over the lifetime of the resource, so they aren't part of its id
+	d.SetId(fmt.Sprintf("%s%s%s", tenantName, TagIdDelimter, resource.UniqueId()))
+	return applyAnnotations(d, meta, tenantName)
+}
+
+func resourceTetrationAnnotationsUpdate(d *schema.ResourceData, meta interface{}) error {
+	return applyAnnotations(d, meta, d.Get("tenant_name").(string))
+}
+
+func resourceTetrationAnnotationsRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(providerMeta).Client
+	rows, err := downloadAnnotations(client, d.Get("tenant_name").(string))
+	if err != nil {
+		return err
+	}
+	managedIps := make(map[string]bool)
+	for _, ip := range d.Get("ips").([]interface{}) {
+		managedIps[canonicalAnnotationIp(ip.(string))] = true
+	}
+	configuredRows, err := annotationRowsFromTerraform(d.Get("row").([]interface{}), d.Get("csv").(string), d.Get("csv_file").(string))
+	if err != nil {
+		return err
+	}
+	// Only compare the annotation keys this resource manages,
+	// other keys may be set by other sources
+	managedKeys := annotationKeys(configuredRows)
+	var managedRows []annotationRow
+	for _, row := range rows {
+		if !managedIps[row.Ip] {
+			continue
+		}
+		managedRow := annotationRow{Ip: row.Ip, Attributes: make(map[string]string)}
+		for _, key := range managedKeys {
+			managedRow.Attributes[key] = row.Attributes[key]
+		}
+		managedRows = append(managedRows, managedRow)
+	}
+	d.Set("content_hash", annotationsHash(managedRows))
+	return nil
+}
+
+func resourceTetrationAnnotationsDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(providerMeta).Client
+	if d.Get("operation").(string) == DeleteAnnotationsOperation {
+		return nil
+	}
+	var rows []annotationRow
+	for _, ip := range d.Get("ips").([]interface{}) {
+		rows = append(rows, annotationRow{Ip: ip.(string)})
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+	_, err := uploadAnnotations(client, d.Get("tenant_name").(string), DeleteAnnotationsOperation, rows)
+	return err
+}
+
+// resourceTetrationAnnotationsCustomizeDiff plans an update whenever the annotations
+// of the managed IPs in Tetration, or the content of csv_file, differ from the configured rows.
+func resourceTetrationAnnotationsCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("row") || !d.NewValueKnown("csv") || !d.NewValueKnown("csv_file") {
+		return d.SetNewComputed("content_hash")
+	}
+	rows, err := annotationRowsFromTerraform(d.Get("row").([]interface{}), d.Get("csv").(string), d.Get("csv_file").(string))
+	if err != nil {
+		return err
+	}
+	for index, row := range rows {
+		if _, err := canonicalTagIp(row.Ip); err != nil {
+			return fmt.Errorf("Annotation row %d: %s", index+1, err)
+		}
+	}
+	desiredHash := desiredAnnotationsHash(rows, d.Get("operation").(string))
+	if d.Id() != "" && d.Get("content_hash").(string) != desiredHash {
+		return d.SetNew("content_hash", desiredHash)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package tetration
+
+import (
+	"testing"
+)
+
+func TestCanonicalAnnotationIp(t *testing.T) {
+	for value, expected := range map[string]string{
+		"10.0.0.1":        "10.0.0.1",
+		"10.0.0.1/32":     "10.0.0.1",
+		"010.0.0.0/24":    "10.0.0.0/24",
+		"2001:DB8::1/128": "2001:db8::1",
+		"2001:db8::/32":   "2001:db8::/32",
+		" not an ip ":     "not an ip",
+	} {
+		if canonical := canonicalAnnotationIp(value); canonical != expected {
+			t.Errorf("Expected %q to be canonicalized to %s, got %s", value, expected, canonical)
+		}
+	}
+}
+
+func TestAnnotationsHashOfEquivalentRows(t *testing.T) {
+	configured, err := annotationRowsFromTerraform([]interface{}{
+		terraformObject{"ip": "10.0.0.1", "attributes": map[string]interface{}{"app": "web"}},
+		terraformObject{"ip": "10.0.1.0/24", "attributes": map[string]interface{}{"app": "db", "owner": ""}},
+	}, "", "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	downloaded, err := annotationRowsFromCSV([]byte("IP,owner,app\n10.0.1.000/24,,db\n10.0.0.1/32,,web\n"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if annotationsHash(configured) != annotationsHash(downloaded) {
+		t.Errorf("Expected %v and %v to have the same hash", configured, downloaded)
+	}
+	changed, err := annotationRowsFromCSV([]byte("IP,app\n10.0.0.1,web\n10.0.1.0/24,cache\n"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if annotationsHash(configured) == annotationsHash(changed) {
+		t.Errorf("Expected %v and %v to have different hashes", configured, changed)
+	}
+}
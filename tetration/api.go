@@ -0,0 +1,92 @@
+package tetration
+
+import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+
+	client "github.com/tetration-exchange/terraform-go-sdk"
+	tetration "github.com/tetration-exchange/terraform-go-sdk"
+	"github.com/tetration-exchange/terraform-go-sdk/signer"
+)
+
+// doRequest signs and sends a JSON request to the given path of the Tetration
+// v1 API, decoding the response into result if result is not nil. It is used
+// for API endpoints that are not (yet) wrapped by the SDK client.
+func doRequest(apiClient client.Client, method string, path string, params interface{}, result interface{}) error {
+	url := apiClient.Config.APIURL + tetration.TetrationAPIV1BasePath + path
+	request, err := signer.CreateJSONRequest(method, url, params)
+	if err != nil {
+		return err
+	}
+	return apiClient.Do(request, result)
+}
+
+// isNotFound returns whether err is the error of a request that failed because
+// the requested object doesn't exist.
+func isNotFound(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "failed with status code 404")
+}
+
+var (
+	insecureRawRequestClient     *http.Client
+	insecureRawRequestClientOnce sync.Once
+)
+
+// rawRequestClient returns the HTTP client the SDK client uses for the given config.
+// The SDK doesn't expose its client, so this mirrors how it is built: the shared
+// default client, or a single client skipping TLS verification that is reused
+// across requests so its connections are pooled.
+func rawRequestClient(config client.Config) *http.Client {
+	if !config.DisableTLSVerification {
+		return http.DefaultClient
+	}
+	insecureRawRequestClientOnce.Do(func() {
+		insecureRawRequestClient = &http.Client{Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		}}
+	})
+	return insecureRawRequestClient
+}
+
+// doRawRequest signs and sends a request with the given content type and body
+// to the given path of the Tetration v1 API, returning the raw response body.
+// It is used for API endpoints that don't exchange JSON, such as file uploads
+// and downloads.
+func doRawRequest(apiClient client.Client, method string, path string, contentType string, body []byte) ([]byte, error) {
+	requestSigner, err := signer.New(apiClient.Config.APIKey, apiClient.Config.APISecret)
+	if err != nil {
+		return nil, err
+	}
+	url := apiClient.Config.APIURL + tetration.TetrationAPIV1BasePath + path
+	request, err := http.NewRequest(method, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if body == nil {
+		request.Body = http.NoBody
+	}
+	request.Header.Set(signer.ContentTypeHeaderKey, contentType)
+	err = requestSigner.Sign(request)
+	if err != nil {
+		return nil, err
+	}
+	response, err := rawRequestClient(apiClient.Config).Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	responseBody, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	if !(response.StatusCode >= 200 && response.StatusCode <= 299) {
+		return nil, fmt.Errorf("Request %s %s\n failed with status code %d\n response %s", method, url,
+			response.StatusCode, responseBody)
+	}
+	return responseBody, nil
+}
@@ -0,0 +1,210 @@
+package tetration
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+	client "github.com/tetration-exchange/terraform-go-sdk"
+)
+
+var (
+	ValidApiKeyCapabilities = []string{
+		"user_role_scope_management",
+		"sensor_management",
+		"hw_sensor_management",
+		"flow_inventory_query",
+		"app_policy_management",
+		"user_data_upload",
+		"external_integration",
+		"software_download",
+	}
+)
+
+func resourceTetrationApiKey() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceTetrationApiKeyCreate,
+		Read:   resourceTetrationApiKeyRead,
+		Delete: resourceTetrationApiKeyDelete,
+
+		SchemaVersion: 2,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Type:    resourceTetrationApiKeyV1().CoreConfigSchema().ImpliedType(),
+				Upgrade: resourceTetrationApiKeyStateUpgradeV1,
+				Version: 1,
+			},
+		},
+
+		Schema: map[string]*schema.Schema{
+			"user_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "ID of the user the API key acts as. The key can't exceed the permissions of the user.",
+			},
+			"capabilities": {
+				Type:     schema.TypeSet,
+				Required: true,
+				ForceNew: true,
+				MinItems: 1,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringInSlice(ValidApiKeyCapabilities, false),
+				},
+				Description: fmt.Sprintf("Capabilities of the API key, any of [%s].", strings.Join(ValidApiKeyCapabilities, ", ")),
+			},
+			"description": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "(Optional) User-specified description of the API key, e.g. the team or pipeline using it.",
+			},
+			"rotation_trigger": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "(Optional) Arbitrary value that replaces the API key with a new key and secret when changed, e.g. a rotation date.",
+			},
+			"key": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The API key, used to sign requests together with the secret.",
+			},
+			"secret": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The API secret. Only returned when the key is created, so it can't be imported.",
+			},
+			"created_at": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "RFC 3339 timestamp of the creation of the API key.",
+			},
+		},
+	}
+}
+
+// apiKey describes an API key of a user. The secret is only returned when the key is created.
+type apiKey struct {
+	Key          string   `json:"key,omitempty"`
+	Secret       string   `json:"secret,omitempty"`
+	UserId       string   `json:"user_id"`
+	Description  string   `json:"description"`
+	Capabilities []string `json:"capabilities"`
+	// Unix timestamp of the creation of the key.
+	CreatedAt int64 `json:"created_at,omitempty"`
+}
+
+// createApiKey creates an API key, returning the key with its secret.
+func createApiKey(apiClient client.Client, params apiKey) (apiKey, error) {
+	var created apiKey
+	err := doRequest(apiClient, http.MethodPost, "/api_keys", params, &created)
+	return created, err
+}
+
+// describeApiKey returns the API key with the given key, without its secret.
+func describeApiKey(apiClient client.Client, key string) (apiKey, error) {
+	var described apiKey
+	err := doRequest(apiClient, http.MethodGet, fmt.Sprintf("/api_keys/%s", key), nil, &described)
+	return described, err
+}
+
+func deleteApiKey(apiClient client.Client, key string) error {
+	return doRequest(apiClient, http.MethodDelete, fmt.Sprintf("/api_keys/%s", key), nil, nil)
+}
+
+func resourceTetrationApiKeyCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(providerMeta).Client
+	created, err := createApiKey(client, apiKey{
+		UserId:       d.Get("user_id").(string),
+		Description:  d.Get("description").(string),
+		Capabilities: stringsFromTerraform(d.Get("capabilities").(*schema.Set).List()),
+	})
+	if err != nil {
+		return err
+	}
+	if created.Key == "" || created.Secret == "" {
+		return fmt.Errorf("Tetration did not return the key and secret of the API key created for user %s", d.Get("user_id").(string))
+	}
+	d.SetId(apiKeyId(created.Key))
+	d.Set("key", created.Key)
+	d.Set("secret", created.Secret)
+	return resourceTetrationApiKeyRead(d, meta)
+}
+
+// resourceTetrationApiKeyRead refreshes the attributes of the API key,
+// keeping the secret from state as it can't be read back.
+func resourceTetrationApiKeyRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(providerMeta).Client
+	described, err := describeApiKey(client, d.Get("key").(string))
+	if isNotFound(err) {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	createdAt := ""
+	if described.CreatedAt > 0 {
+		createdAt = time.Unix(described.CreatedAt, 0).UTC().Format(time.RFC3339)
+	}
+	d.Set("user_id", described.UserId)
+	d.Set("description", described.Description)
+	d.Set("capabilities", described.Capabilities)
+	d.Set("created_at", createdAt)
+	return nil
+}
+
+func resourceTetrationApiKeyDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(providerMeta).Client
+	err := deleteApiKey(client, d.Get("key").(string))
+	if isNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// apiKeyId returns the id of the resource of an API key. The key is
+// sensitive, so the id is a hash of it rather than the key itself.
+func apiKeyId(key string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(key)))
+}
+
+// resourceTetrationApiKeyV1 returns the schema of API keys with version 1 ids,
+// which were the key itself.
+func resourceTetrationApiKeyV1() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"user_id":          {Type: schema.TypeString, Required: true},
+			"capabilities":     {Type: schema.TypeSet, Required: true, Elem: &schema.Schema{Type: schema.TypeString}},
+			"description":      {Type: schema.TypeString, Optional: true},
+			"rotation_trigger": {Type: schema.TypeString, Optional: true},
+			"key":              {Type: schema.TypeString, Computed: true, Sensitive: true},
+			"secret":           {Type: schema.TypeString, Computed: true, Sensitive: true},
+			"created_at":       {Type: schema.TypeString, Computed: true},
+		},
+	}
+}
+
+// resourceTetrationApiKeyStateUpgradeV1 replaces version 1 ids with the hash
+// of the key, restoring the key from the old id if it is missing from state.
+func resourceTetrationApiKeyStateUpgradeV1(rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	key, _ := rawState["key"].(string)
+	if key == "" {
+		key, _ = rawState["id"].(string)
+		rawState["key"] = key
+	}
+	if key == "" {
+		return rawState, errors.New("Unable to upgrade API key state without its key")
+	}
+	rawState["id"] = apiKeyId(key)
+	return rawState, nil
+}
@@ -0,0 +1,38 @@
+package tetration
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestApiKeyStateUpgradeV1(t *testing.T) {
+	upgraded, err := resourceTetrationApiKeyStateUpgradeV1(map[string]interface{}{"id": "abc", "key": "abc"}, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if upgraded["id"] != apiKeyId("abc") || upgraded["key"] != "abc" {
+		t.Errorf("Expected hashed id and unchanged key, got %v", upgraded)
+	}
+	upgraded, err = resourceTetrationApiKeyStateUpgradeV1(map[string]interface{}{"id": "abc"}, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if upgraded["id"] != apiKeyId("abc") || upgraded["key"] != "abc" {
+		t.Errorf("Expected hashed id and key restored from id, got %v", upgraded)
+	}
+	if _, err := resourceTetrationApiKeyStateUpgradeV1(map[string]interface{}{}, nil); err == nil {
+		t.Errorf("Expected an error for state without a key")
+	}
+}
+
+func TestIsNotFound(t *testing.T) {
+	apiClient, closeServer := testAgentsClient(t, map[string]agentsResponse{})
+	defer closeServer()
+	err := doRequest(apiClient, http.MethodGet, "/api_keys/abc", nil, nil)
+	if !isNotFound(err) {
+		t.Errorf("Expected a not found error, got %v", err)
+	}
+	if isNotFound(nil) {
+		t.Errorf("Expected nil not to be a not found error")
+	}
+}
@@ -3,8 +3,10 @@ package tetration
 import (
 	"errors"
 	"fmt"
+	"sort"
 
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
 	client "github.com/tetration-exchange/terraform-go-sdk"
 	tetration "github.com/tetration-exchange/terraform-go-sdk"
 )
@@ -12,8 +14,10 @@ import (
 func resourceTetrationApplication() *schema.Resource {
 	return &schema.Resource{
 		Create:        resourceTetrationApplicationCreate,
+		Update:        resourceTetrationApplicationUpdate,
 		Read:          resourceTetrationApplicationRead,
 		Delete:        resourceTetrationApplicationDelete,
+		CustomizeDiff: resourceTetrationApplicationCustomizeDiff,
 		SchemaVersion: 1,
 		Schema: map[string]*schema.Schema{
 			"app_scope_id": {
@@ -60,10 +64,14 @@ func resourceTetrationApplication() *schema.Resource {
 			"cluster": {
 				Type:        schema.TypeList,
 				Optional:    true,
-				Description: "Cluster wraps a groups of nodes to be used to define policies.",
-				ForceNew:    true,
+				Description: "Cluster wraps a groups of nodes, or a query in dynamic mode, to be used to define policies. Adding or removing clusters, or changing their id, recreates the application.",
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
+						"cluster_id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "ID assigned to the cluster by Tetration.",
+						},
 						"id": {
 							Type:        schema.TypeString,
 							Optional:    true,
@@ -72,6 +80,7 @@ func resourceTetrationApplication() *schema.Resource {
 						"name": {
 							Type:        schema.TypeString,
 							Optional:    true,
+							Computed:    true,
 							Description: " Cluster display name.",
 						},
 						"description": {
@@ -101,8 +110,43 @@ func resourceTetrationApplication() *schema.Resource {
 						"consistent_uuid": {
 							Type:        schema.TypeString,
 							Optional:    true,
+							Computed:    true,
 							Description: "Must be unique to a given application. After an ADM run, the similar/same clusters in the next version will maintain the consistent_uuid.",
 						},
+						"query": {
+							Type:             schema.TypeString,
+							Optional:         true,
+							ValidateFunc:     validation.ValidateJsonString,
+							DiffSuppressFunc: suppressEquivalentJSONDiffs,
+							Description:      "(Optional) JSON object representation of the query defining the cluster in dynamic mode. Only one of node, query or query_type/query_field/query_value can be specified.",
+						},
+						"query_type": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "(Optional) Type of a single condition query defining the cluster in dynamic mode; for example, eq or subnet.",
+						},
+						"query_field": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "(Optional) Inventory field of a single condition query defining the cluster in dynamic mode; for example, ip.",
+						},
+						"query_value": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "(Optional) Value of a single condition query defining the cluster in dynamic mode.",
+						},
+						"approved": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+							Description: "(Optional) Indicates whether the cluster is approved, approved clusters are kept as is by ADM runs. Default is false.",
+						},
+						"external": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+							Description: "(Optional) Indicates whether the cluster contains endpoints outside of the application scope. Default is false.",
+						},
 					},
 				},
 			},
@@ -134,10 +178,19 @@ func resourceTetrationApplication() *schema.Resource {
 			"absolute_policy": {
 				Type:        schema.TypeList,
 				Optional:    true,
-				ForceNew:    true,
-				Description: "Ordered application policy to be created with the absolute rank.",
+				Description: "Ordered application policy to be created with the absolute rank. Reordering policies or moving them between absolute_policy and default_policy updates their priority and rank in place, any other change recreates the application.",
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Unique identifier of the policy.",
+						},
+						"priority": {
+							Type:        schema.TypeInt,
+							Optional:    true,
+							Description: "(Optional) Priority of the policy within its rank, lower values take precedence. Defaults to the position of the policy in the list.",
+						},
 						"consumer_filter_id": {
 							Type:        schema.TypeString,
 							Optional:    true,
@@ -168,6 +221,16 @@ func resourceTetrationApplication() *schema.Resource {
 							Optional:    true,
 							Description: "Named application scope. If more than one application scope with the same name exists you must specify consumer_filter_id. Only one of consumer_filter_id, consumer_filter_name or consumer_scope_name can be specified.",
 						},
+						"provider_application_id": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "ID of another application providing the service named by provider_service_name. Must be specified together with provider_service_name, instead of provider_filter_id, provider_filter_name or provider_scope_name.",
+						},
+						"provider_service_name": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Name of a public inventory filter (provided service) in the scope of the application provider_application_id.",
+						},
 						"action": {
 							Type:        schema.TypeString,
 							Optional:    true,
@@ -209,10 +272,19 @@ func resourceTetrationApplication() *schema.Resource {
 			"default_policy": {
 				Type:        schema.TypeList,
 				Optional:    true,
-				ForceNew:    true,
-				Description: "Ordered application policy to be created with the default rank.",
+				Description: "Ordered application policy to be created with the default rank. Reordering policies or moving them between absolute_policy and default_policy updates their priority and rank in place, any other change recreates the application.",
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Unique identifier of the policy.",
+						},
+						"priority": {
+							Type:        schema.TypeInt,
+							Optional:    true,
+							Description: "(Optional) Priority of the policy within its rank, lower values take precedence. Defaults to the position of the policy in the list.",
+						},
 						"consumer_filter_id": {
 							Type:        schema.TypeString,
 							Optional:    true,
@@ -243,6 +315,16 @@ func resourceTetrationApplication() *schema.Resource {
 							Optional:    true,
 							Description: "Named application scope. If more than one application scope with the same name exists you must specify consumer_filter_id. Only one of consumer_filter_id, consumer_filter_name or consumer_scope_name can be specified.",
 						},
+						"provider_application_id": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "ID of another application providing the service named by provider_service_name. Must be specified together with provider_service_name, instead of provider_filter_id, provider_filter_name or provider_scope_name.",
+						},
+						"provider_service_name": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Name of a public inventory filter (provided service) in the scope of the application provider_application_id.",
+						},
 						"action": {
 							Type:        schema.TypeString,
 							Optional:    true,
@@ -285,9 +367,51 @@ func resourceTetrationApplication() *schema.Resource {
 			"catch_all_action": {
 				Type:        schema.TypeString,
 				Required:    true,
-				ForceNew:    true,
 				Description: "“ALLOW” or “DENY”",
 			},
+			"external_dependencies": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Services provided by other applications that policies of this application depend on. Policies are updated in place when a service resolves to a different inventory filter.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"application_id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "ID of the application providing the service.",
+						},
+						"service_name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Name of the provided service.",
+						},
+						"filter_id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "ID of the inventory filter the service resolved to.",
+						},
+					},
+				},
+			},
+			"provided_services": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Public inventory filters in the scope of the application that other applications can use as provider_service_name.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "ID of the inventory filter.",
+						},
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Name of the inventory filter.",
+						},
+					},
+				},
+			},
 			"author": {
 				Type:        schema.TypeString,
 				Computed:    true,
@@ -318,7 +442,7 @@ func resourceTetrationApplication() *schema.Resource {
 }
 
 func resourceTetrationApplicationCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(client.Client)
+	client := meta.(providerMeta).Client
 	isPrimaryApplication := d.Get("primary").(bool)
 	if isPrimaryApplication {
 		existingApplications, err := client.ListApplications()
@@ -331,17 +455,19 @@ func resourceTetrationApplicationCreate(d *schema.ResourceData, meta interface{}
 			}
 		}
 	}
-	createApplicationParams := tetration.CreateApplicationRequest{
-		AppScopeId:         d.Get("app_scope_id").(string),
-		Name:               d.Get("name").(string),
-		Description:        d.Get("description").(string),
-		AlternateQueryMode: d.Get("alternate_query_mode").(bool),
-		StrictValidation:   d.Get("strict_validation").(bool),
-		Primary:            isPrimaryApplication,
-		CatchAllAction:     d.Get("catch_all_action").(string),
+	createApplicationParams := createApplicationRequest{
+		CreateApplicationRequest: tetration.CreateApplicationRequest{
+			AppScopeId:         d.Get("app_scope_id").(string),
+			Name:               d.Get("name").(string),
+			Description:        d.Get("description").(string),
+			AlternateQueryMode: d.Get("alternate_query_mode").(bool),
+			StrictValidation:   d.Get("strict_validation").(bool),
+			Primary:            isPrimaryApplication,
+			CatchAllAction:     d.Get("catch_all_action").(string),
+		},
 	}
 	if value, ok := d.GetOk("cluster"); ok {
-		var clusters []tetration.Cluster
+		var clusters []applicationCluster
 		tfClusters := value.([]interface{})
 		for _, tfCluster := range tfClusters {
 			if tfCluster == nil {
@@ -370,6 +496,11 @@ func resourceTetrationApplicationCreate(d *schema.ResourceData, meta interface{}
 		}
 		createApplicationParams.Filters = filters
 	}
+	dependencies, _, err := externalDependenciesFromTerraform(client, d.Get("absolute_policy"), d.Get("default_policy"))
+	if err != nil {
+		return err
+	}
+	serviceFilterIds := externalDependencyFilterIds(dependencies)
 	if value, ok := d.GetOk("absolute_policy"); ok {
 		var absolutePolicies []tetration.Policy
 		tfAbsolutePolicies := value.([]interface{})
@@ -377,7 +508,7 @@ func resourceTetrationApplicationCreate(d *schema.ResourceData, meta interface{}
 			if tfAbsolutePolicy == nil {
 				continue
 			}
-			abosolutePolicy, err := policyFromTerraform(client, tfAbsolutePolicy.(terraformObject))
+			abosolutePolicy, err := policyFromTerraform(client, tfAbsolutePolicy.(terraformObject), serviceFilterIds)
 			if err != nil {
 				return err
 			}
@@ -392,7 +523,7 @@ func resourceTetrationApplicationCreate(d *schema.ResourceData, meta interface{}
 			if tfDefaultPolicy == nil {
 				continue
 			}
-			abosolutePolicy, err := policyFromTerraform(client, tfDefaultPolicy.(terraformObject))
+			abosolutePolicy, err := policyFromTerraform(client, tfDefaultPolicy.(terraformObject), serviceFilterIds)
 			if err != nil {
 				return err
 			}
@@ -400,23 +531,231 @@ func resourceTetrationApplicationCreate(d *schema.ResourceData, meta interface{}
 		}
 		createApplicationParams.DefaultPolicies = defaultPolicies
 	}
-	application, err := client.CreateApplication(createApplicationParams)
+	application, err := createApplication(client, createApplicationParams)
 	if err != nil {
 		return err
 	}
+	d.Set("external_dependencies", dependencies)
 	d.Set("author", application.Author)
 	d.Set("created_at", application.CreatedAt)
 	d.Set("latest_adm_version", application.LatestADMVersion)
 	d.Set("enforcement_enabled", application.EnforcementEnabled)
 	d.Set("enforced_version", application.EnforcedVersion)
 	d.SetId(application.Id)
+	err = readApplicationClusters(client, d)
+	if err != nil {
+		return err
+	}
+	return updateApplicationPolicyOrder(client, d, map[string][]string{})
+}
+
+func resourceTetrationApplicationUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(providerMeta).Client
+	if d.HasChange("catch_all_action") {
+		err := updateCatchAllAction(client, d.Id(), d.Get("catch_all_action").(string))
+		if err != nil {
+			return err
+		}
+	}
+	if d.HasChange("cluster") {
+		err := updateApplicationClusters(client, d)
+		if err != nil {
+			return err
+		}
+	}
+	if d.HasChange("absolute_policy") || d.HasChange("default_policy") {
+		// Policies can only be reordered in place (see resourceTetrationApplicationCustomizeDiff)
+		// so every configured policy has a previously known counterpart with the same content
+		previousPolicyIds := make(map[string][]string)
+		for _, policyRank := range applicationPolicyRanks {
+			tfPolicies, _ := d.GetChange(policyRank.attribute)
+			for _, tfPolicy := range tfPolicies.([]interface{}) {
+				if tfPolicy == nil {
+					continue
+				}
+				tf := tfPolicy.(terraformObject)
+				if tf["id"].(string) == "" {
+					continue
+				}
+				key := policyKey(tf)
+				previousPolicyIds[key] = append(previousPolicyIds[key], tf["id"].(string))
+			}
+		}
+		err := updateApplicationPolicyOrder(client, d, previousPolicyIds)
+		if err != nil {
+			return err
+		}
+	}
+	if d.HasChange("external_dependencies") {
+		err := updateExternalDependencies(client, d)
+		if err != nil {
+			return err
+		}
+	}
+	return resourceTetrationApplicationRead(d, meta)
+}
+
+// resourceTetrationApplicationCustomizeDiff recreates the application if
+// policies were added, removed or changed, allowing policies that were only
+// reordered or moved to a different rank to be updated in place.
+// Policies referencing services of other applications are validated against the
+// Tetration API, and are updated in place if a service now resolves to a
+// different inventory filter than the one the policies use.
+func resourceTetrationApplicationCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	if meta == nil {
+		return nil
+	}
+	client := meta.(providerMeta).Client
+	var tfClusters []interface{}
+	if d.NewValueKnown("cluster") {
+		tfClusters = d.Get("cluster").([]interface{})
+	}
+	for index, tfCluster := range tfClusters {
+		if tfCluster == nil {
+			continue
+		}
+		// Unknown values read as zero values, so clusters built from values
+		// that are only known after apply are validated on apply
+		if !blockAttributesKnown(d, fmt.Sprintf("cluster.%d", index), clusterDefinitionAttributes) {
+			continue
+		}
+		tf := tfCluster.(terraformObject)
+		if err := validateClusterFromTerraform(tf); err != nil {
+			return fmt.Errorf("cluster.%d: %s", index, err)
+		}
+		isQueryCluster := tf["query"].(string) != "" || tf["query_type"].(string) != ""
+		if isQueryCluster && d.NewValueKnown("alternate_query_mode") && !d.Get("alternate_query_mode").(bool) {
+			return fmt.Errorf("cluster.%d: clusters can only be defined by a query when alternate_query_mode (dynamic mode) is enabled", index)
+		}
+	}
+	referencesKnown := true
+	for _, policyRank := range applicationPolicyRanks {
+		if !d.NewValueKnown(policyRank.attribute) {
+			referencesKnown = false
+			continue
+		}
+		for index, tfPolicy := range d.Get(policyRank.attribute).([]interface{}) {
+			if tfPolicy == nil {
+				continue
+			}
+			// Unknown values read as empty strings, so policies referencing
+			// resources that don't exist yet are only validated on apply
+			if !blockAttributesKnown(d, fmt.Sprintf("%s.%d", policyRank.attribute, index), policyFilterQueryAttributes) {
+				referencesKnown = false
+				continue
+			}
+			consumer, provider := policyFilterQueriesFromTerraform(tfPolicy.(terraformObject))
+			for _, query := range []policyFilterQuery{consumer, provider} {
+				if err := query.validate(); err != nil {
+					return fmt.Errorf("%s.%d: %s", policyRank.attribute, index, err)
+				}
+			}
+		}
+	}
+	dependencies, known, err := externalDependenciesFromTerraform(client, d.Get("absolute_policy"), d.Get("default_policy"))
+	if err != nil {
+		return err
+	}
+	if d.Id() == "" {
+		return nil
+	}
+	if d.HasChange("cluster") && clusterIdentitiesChanged(d.GetChange("cluster")) {
+		if err := d.ForceNew("cluster"); err != nil {
+			return err
+		}
+	}
+	if known && referencesKnown && externalDependenciesKey(dependencies) != externalDependenciesKey(d.Get("external_dependencies").([]interface{})) {
+		if err := d.SetNew("external_dependencies", dependencies); err != nil {
+			return err
+		}
+	}
+	oldAbsolutePolicies, newAbsolutePolicies := d.GetChange("absolute_policy")
+	oldDefaultPolicies, newDefaultPolicies := d.GetChange("default_policy")
+	if samePolicyKeys(policyKeys(oldAbsolutePolicies, oldDefaultPolicies), policyKeys(newAbsolutePolicies, newDefaultPolicies)) {
+		return nil
+	}
+	for _, policyRank := range applicationPolicyRanks {
+		if d.HasChange(policyRank.attribute) {
+			err := d.ForceNew(policyRank.attribute)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// updateApplicationPolicyOrder updates the priority and rank of each policy of
+// the application to match its position in the absolute_policy and
+// default_policy blocks, recording the id of each policy in state. Policy
+// blocks are matched to policies by previousPolicyIds (content key to ids), any
+// blocks left unmatched are matched in order against the remaining policies of
+// the same rank.
+func updateApplicationPolicyOrder(apiClient client.Client, d *schema.ResourceData, previousPolicyIds map[string][]string) error {
+	policies, err := describeApplicationPolicies(apiClient, d.Id())
+	if err != nil {
+		return err
+	}
+	policiesByRank := policies.byRank()
+	existingPolicies := policies.byId()
+	tfPoliciesByRank := make(map[string][]interface{})
+	claimedPolicyIds := make(map[string]bool)
+	for _, policyRank := range applicationPolicyRanks {
+		tfPolicies := d.Get(policyRank.attribute).([]interface{})
+		for _, tfPolicy := range tfPolicies {
+			if tfPolicy == nil {
+				continue
+			}
+			tf := tfPolicy.(terraformObject)
+			tf["id"] = ""
+			key := policyKey(tf)
+			if policyIds := previousPolicyIds[key]; len(policyIds) > 0 {
+				tf["id"] = policyIds[0]
+				claimedPolicyIds[policyIds[0]] = true
+				previousPolicyIds[key] = policyIds[1:]
+			}
+		}
+		tfPoliciesByRank[policyRank.rank] = tfPolicies
+	}
+	for _, policyRank := range applicationPolicyRanks {
+		tfPolicies := tfPoliciesByRank[policyRank.rank]
+		assignPolicyIds(tfPolicies, policiesByRank[policyRank.rank], claimedPolicyIds)
+		for index, tfPolicy := range tfPolicies {
+			if tfPolicy == nil {
+				continue
+			}
+			tf := tfPolicy.(terraformObject)
+			policy, ok := existingPolicies[tf["id"].(string)]
+			if !ok {
+				return fmt.Errorf("Unable to find a policy of application %s matching %s policy %d", d.Id(), policyRank.attribute, index)
+			}
+			priority := tf["priority"].(int)
+			if priority == 0 {
+				priority = index + 1
+			}
+			if policy.Rank == policyRank.rank && policy.Priority == priority {
+				continue
+			}
+			_, err := updatePolicy(apiClient, policy.Id, updatePolicyRequest{
+				Priority: priority,
+				Rank:     policyRank.rank,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		d.Set(policyRank.attribute, tfPolicies)
+	}
 	return nil
 }
 
 type terraformObject = map[string]interface{}
 
-func clusterFromTerraform(tf terraformObject) (tetration.Cluster, error) {
-	cluster := tetration.Cluster{}
+func clusterFromTerraform(tf terraformObject) (applicationCluster, error) {
+	cluster := applicationCluster{}
+	if err := validateClusterFromTerraform(tf); err != nil {
+		return cluster, err
+	}
 	cluster.Id = tf["id"].(string)
 	cluster.Name = tf["name"].(string)
 	cluster.Description = tf["description"].(string)
@@ -432,6 +771,13 @@ func clusterFromTerraform(tf terraformObject) (tetration.Cluster, error) {
 		cluster.Nodes = nodes
 	}
 	cluster.ConsistentUUID = tf["consistent_uuid"].(string)
+	query, err := clusterQueryFromTerraform(tf)
+	if err != nil {
+		return cluster, err
+	}
+	cluster.Query = query
+	cluster.Approved = tf["approved"].(bool)
+	cluster.External = tf["external"].(bool)
 	return cluster, nil
 }
 
@@ -451,24 +797,47 @@ func filterFromTerraform(tf terraformObject) (tetration.PolicyFilter, error) {
 }
 
 type policyFilterQuery struct {
-	AbsoluteId string
-	FilterName string
-	ScopeName  string
+	AbsoluteId    string
+	FilterName    string
+	ScopeName     string
+	ApplicationId string
+	ServiceName   string
 }
 
-func policyFilterIdForQuery(apiClient client.Client, query policyFilterQuery) (string, error) {
+// validate validates that exactly one way of referencing a policy filter was
+// specified.
+func (query policyFilterQuery) validate() error {
+	if query.ApplicationId != "" || query.ServiceName != "" {
+		if query.ApplicationId == "" || query.ServiceName == "" {
+			return errors.New("Both provider application id and provider service name must be specified")
+		}
+		if query.AbsoluteId != "" || query.FilterName != "" || query.ScopeName != "" {
+			return errors.New("Only one of policy filter id, filter name, scope name or provider service name can be specified")
+		}
+		return nil
+	}
 	if query.AbsoluteId == "" && query.FilterName == "" && query.ScopeName == "" {
-		return "", errors.New("One  of policy filter id, filter name or scope name must be specified")
+		return errors.New("One  of policy filter id, filter name or scope name must be specified")
 	}
 	if query.AbsoluteId != "" && (query.FilterName != "" || query.ScopeName != "") {
-		return "", errors.New("Only one of policy filter id, filter name or scope name can be specified")
+		return errors.New("Only one of policy filter id, filter name or scope name can be specified")
 	}
 	if query.FilterName != "" && query.ScopeName != "" {
-		return "", errors.New("Only one of policy filter id, filter name or scope name can be specified")
+		return errors.New("Only one of policy filter id, filter name or scope name can be specified")
+	}
+	return nil
+}
+
+func policyFilterIdForQuery(apiClient client.Client, query policyFilterQuery) (string, error) {
+	if err := query.validate(); err != nil {
+		return "", err
 	}
 	if query.AbsoluteId != "" {
 		return query.AbsoluteId, nil
 	}
+	if query.ServiceName != "" {
+		return providedServiceFilterId(apiClient, query.ApplicationId, query.ServiceName)
+	}
 	var tetrationPolicyFilterId string
 	if query.FilterName != "" {
 		inventoryFilters, err := apiClient.ListFilters()
@@ -481,6 +850,9 @@ func policyFilterIdForQuery(apiClient client.Client, query policyFilterQuery) (s
 				filtersWithMatchingName = append(filtersWithMatchingName, inventoryFilter)
 			}
 		}
+		if len(filtersWithMatchingName) == 0 {
+			return "", errors.New(fmt.Sprintf("No filter exists with name %s.", query.FilterName))
+		}
 		if len(filtersWithMatchingName) > 1 {
 			return "", errors.New(fmt.Sprintf("More than one filter exists with name %s, please use policy filter id to specify the exact one to use.", query.FilterName))
 		}
@@ -497,6 +869,9 @@ func policyFilterIdForQuery(apiClient client.Client, query policyFilterQuery) (s
 				scopesWithMatchingName = append(scopesWithMatchingName, scope)
 			}
 		}
+		if len(scopesWithMatchingName) == 0 {
+			return "", errors.New(fmt.Sprintf("No scope exists with name %s.", query.ScopeName))
+		}
 		if len(scopesWithMatchingName) > 1 {
 			return "", errors.New(fmt.Sprintf("More than one scope exists with name %s, please use policy filter id to specify the exact one to use.", query.ScopeName))
 		}
@@ -505,30 +880,73 @@ func policyFilterIdForQuery(apiClient client.Client, query policyFilterQuery) (s
 	return tetrationPolicyFilterId, nil
 }
 
-func policyFromTerraform(apiClient client.Client, tf terraformObject) (tetration.Policy, error) {
-	policy := tetration.Policy{}
-	// Allow users to specify a consumer or provider filter via
-	// absolute id OR scope name OR filter name
-	// returning an error if either more than one scope/filter exists
-	// with the same name or if both an absolute id and name was provided
-	consumingPolicyFilterQuery := policyFilterQuery{
+var clusterDefinitionAttributes = []string{
+	"node",
+	"query",
+	"query_type",
+	"query_field",
+	"query_value",
+}
+
+var policyFilterQueryAttributes = []string{
+	"consumer_filter_id",
+	"consumer_filter_name",
+	"consumer_scope_name",
+	"provider_filter_id",
+	"provider_filter_name",
+	"provider_scope_name",
+	"provider_application_id",
+	"provider_service_name",
+}
+
+// blockAttributesKnown returns whether the planned values of the given
+// attributes of the block at prefix (e.g. absolute_policy.0) are known.
+func blockAttributesKnown(d *schema.ResourceDiff, prefix string, attributes []string) bool {
+	for _, attribute := range attributes {
+		if !d.NewValueKnown(prefix + "." + attribute) {
+			return false
+		}
+	}
+	return true
+}
+
+// policyFilterQueriesFromTerraform returns the queries for
+// the consumer and provider filters of a policy block.
+func policyFilterQueriesFromTerraform(tf terraformObject) (policyFilterQuery, policyFilterQuery) {
+	consumer := policyFilterQuery{
 		AbsoluteId: tf["consumer_filter_id"].(string),
 		FilterName: tf["consumer_filter_name"].(string),
 		ScopeName:  tf["consumer_scope_name"].(string),
 	}
+	provider := policyFilterQuery{
+		AbsoluteId:    tf["provider_filter_id"].(string),
+		FilterName:    tf["provider_filter_name"].(string),
+		ScopeName:     tf["provider_scope_name"].(string),
+		ApplicationId: tf["provider_application_id"].(string),
+		ServiceName:   tf["provider_service_name"].(string),
+	}
+	return consumer, provider
+}
+
+func policyFromTerraform(apiClient client.Client, tf terraformObject, serviceFilterIds map[[2]string]string) (tetration.Policy, error) {
+	policy := tetration.Policy{}
+	// Allow users to specify a consumer or provider filter via
+	// absolute id OR scope name OR filter name (OR for providers
+	// a service provided by another application)
+	// returning an error if either more than one scope/filter exists
+	// with the same name or if both an absolute id and name was provided
+	consumingPolicyFilterQuery, providingPolicyFilterQuery := policyFilterQueriesFromTerraform(tf)
 	filterId, err := policyFilterIdForQuery(apiClient, consumingPolicyFilterQuery)
 	if err != nil {
 		return policy, err
 	}
 	policy.ConsumerFilterId = filterId
-	providingPolicyFilterQuery := policyFilterQuery{
-		AbsoluteId: tf["provider_filter_id"].(string),
-		FilterName: tf["provider_filter_name"].(string),
-		ScopeName:  tf["provider_scope_name"].(string),
-	}
-	filterId, err = policyFilterIdForQuery(apiClient, providingPolicyFilterQuery)
-	if err != nil {
-		return policy, err
+	filterId, ok := serviceFilterIds[[2]string{providingPolicyFilterQuery.ApplicationId, providingPolicyFilterQuery.ServiceName}]
+	if !ok {
+		filterId, err = policyFilterIdForQuery(apiClient, providingPolicyFilterQuery)
+		if err != nil {
+			return policy, err
+		}
 	}
 	policy.ProviderFilterId = filterId
 	policy.Action = tf["action"].(string)
@@ -556,7 +974,7 @@ func layer4NetworkPolicyFromTerraform(tf terraformObject) tetration.Layer4Networ
 }
 
 func resourceTetrationApplicationRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(client.Client)
+	client := meta.(providerMeta).Client
 	describeApplicatioParams := tetration.DescribeApplicationRequest{
 		ApplicationId: d.Id(),
 	}
@@ -571,10 +989,77 @@ func resourceTetrationApplicationRead(d *schema.ResourceData, meta interface{})
 	d.Set("latest_adm_version", application.LatestADMVersion)
 	d.Set("enforcement_enabled", application.EnforcementEnabled)
 	d.Set("enforced_version", application.EnforcedVersion)
+	services, err := providedServices(client, application.AppScopeId)
+	if err != nil {
+		return err
+	}
+	tfServices := make([]interface{}, 0, len(services))
+	for _, service := range services {
+		tfServices = append(tfServices, terraformObject{
+			"id":   service.Id,
+			"name": service.Name,
+		})
+	}
+	d.Set("provided_services", tfServices)
+	err = readApplicationClusters(client, d)
+	if err != nil {
+		return err
+	}
+	return readApplicationPolicies(client, d)
+}
+
+// readApplicationPolicies sets the catch all action and the policy blocks
+// of the application in the effective order of their rank and priority,
+// so that policies reordered outside of terraform are reported as drift.
+func readApplicationPolicies(apiClient client.Client, d *schema.ResourceData) error {
+	policies, err := describeApplicationPolicies(apiClient, d.Id())
+	if err != nil {
+		return err
+	}
+	d.Set("catch_all_action", policies.CatchAllAction)
+	policiesByRank := policies.byRank()
+	existingPolicies := policies.byId()
+	claimedPolicyIds := make(map[string]bool)
+	var tfPolicies []interface{}
+	for _, policyRank := range applicationPolicyRanks {
+		rankTfPolicies := d.Get(policyRank.attribute).([]interface{})
+		for _, tfPolicy := range rankTfPolicies {
+			if tfPolicy != nil && tfPolicy.(terraformObject)["id"].(string) != "" {
+				claimedPolicyIds[tfPolicy.(terraformObject)["id"].(string)] = true
+			}
+		}
+		// Policies created before their ids were tracked are matched by position
+		assignPolicyIds(rankTfPolicies, policiesByRank[policyRank.rank], claimedPolicyIds)
+		tfPolicies = append(tfPolicies, rankTfPolicies...)
+	}
+	for _, policyRank := range applicationPolicyRanks {
+		var rankTfPolicies []interface{}
+		for _, tfPolicy := range tfPolicies {
+			if tfPolicy == nil {
+				continue
+			}
+			tf := tfPolicy.(terraformObject)
+			policy, ok := existingPolicies[tf["id"].(string)]
+			// Policies deleted outside of terraform are dropped from state
+			if !ok || policy.Rank != policyRank.rank {
+				continue
+			}
+			if tf["priority"].(int) != 0 {
+				tf["priority"] = policy.Priority
+			}
+			rankTfPolicies = append(rankTfPolicies, tf)
+		}
+		sort.SliceStable(rankTfPolicies, func(i, j int) bool {
+			return existingPolicies[rankTfPolicies[i].(terraformObject)["id"].(string)].Priority <
+				existingPolicies[rankTfPolicies[j].(terraformObject)["id"].(string)].Priority
+		})
+		d.Set(policyRank.attribute, rankTfPolicies)
+	}
+	d.Set("external_dependencies", appliedExternalDependencies(existingPolicies, d.Get("absolute_policy"), d.Get("default_policy")))
 	return nil
 }
 
 func resourceTetrationApplicationDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(client.Client)
+	client := meta.(providerMeta).Client
 	return client.DeleteApplication(d.Id())
 }
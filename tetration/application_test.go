@@ -0,0 +1,59 @@
+package tetration
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/configs/hcl2shim"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestApplicationCustomizeDiffUnknownPolicyReferences(t *testing.T) {
+	for _, test := range []struct {
+		policy   map[string]interface{}
+		expected string
+	}{
+		{map[string]interface{}{"consumer_filter_id": hcl2shim.UnknownVariableValue, "provider_filter_id": "f1"}, ""},
+		{map[string]interface{}{"consumer_filter_id": "f1", "provider_application_id": hcl2shim.UnknownVariableValue, "provider_service_name": "db"}, ""},
+		{map[string]interface{}{"consumer_filter_id": "f1"}, "absolute_policy.0: One  of policy filter id"},
+		{map[string]interface{}{"consumer_filter_id": "f1", "provider_filter_id": "f2", "provider_scope_name": "scope"}, "absolute_policy.0: Only one of"},
+	} {
+		test.policy["action"] = "ALLOW"
+		config := terraform.NewResourceConfigRaw(map[string]interface{}{
+			"app_scope_id":    "scope",
+			"absolute_policy": []interface{}{test.policy},
+		})
+		_, err := resourceTetrationApplication().Diff(nil, config, providerMeta{})
+		if test.expected == "" && err != nil {
+			t.Errorf("Expected no error for %v, got %s", test.policy, err)
+		}
+		if test.expected != "" && (err == nil || !strings.Contains(err.Error(), test.expected)) {
+			t.Errorf("Expected error %q for %v, got %v", test.expected, test.policy, err)
+		}
+	}
+}
+
+func TestApplicationCustomizeDiffUnknownClusters(t *testing.T) {
+	for _, test := range []struct {
+		raw      map[string]interface{}
+		cluster  map[string]interface{}
+		expected string
+	}{
+		{map[string]interface{}{}, map[string]interface{}{"query_type": hcl2shim.UnknownVariableValue, "query_field": "ip", "query_value": "10.0.0.1"}, ""},
+		{map[string]interface{}{}, map[string]interface{}{"query": hcl2shim.UnknownVariableValue, "query_type": "eq", "query_field": "ip"}, ""},
+		{map[string]interface{}{"alternate_query_mode": hcl2shim.UnknownVariableValue}, map[string]interface{}{"query_type": "eq", "query_field": "ip", "query_value": "10.0.0.1"}, ""},
+		{map[string]interface{}{"alternate_query_mode": false}, map[string]interface{}{"query_type": "eq", "query_field": "ip", "query_value": "10.0.0.1"}, "cluster.0: clusters can only be defined by a query"},
+		{map[string]interface{}{}, map[string]interface{}{"query_value": "10.0.0.1"}, "cluster.0: query_type and query_field must be specified"},
+	} {
+		test.cluster["id"] = "cluster"
+		test.raw["app_scope_id"] = "scope"
+		test.raw["cluster"] = []interface{}{test.cluster}
+		_, err := resourceTetrationApplication().Diff(nil, terraform.NewResourceConfigRaw(test.raw), providerMeta{})
+		if test.expected == "" && err != nil {
+			t.Errorf("Expected no error for %v, got %s", test.raw, err)
+		}
+		if test.expected != "" && (err == nil || !strings.Contains(err.Error(), test.expected)) {
+			t.Errorf("Expected error %q for %v, got %v", test.expected, test.raw, err)
+		}
+	}
+}
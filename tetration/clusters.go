@@ -0,0 +1,308 @@
+package tetration
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	client "github.com/tetration-exchange/terraform-go-sdk"
+	tetration "github.com/tetration-exchange/terraform-go-sdk"
+)
+
+// applicationCluster wraps a cluster of an application, including the
+// query, approval and external attributes used by applications in dynamic mode.
+type applicationCluster struct {
+	// Unique identifier to be used with policies.
+	Id string `json:"id"`
+	// Cluster display name.
+	Name string `json:"name"`
+	// Description of the cluster.
+	Description string `json:"description"`
+	// Nodes or endpoints that are part of the cluster.
+	Nodes []tetration.Node `json:"nodes,omitempty"`
+	// Must be unique to a given application. After an ADM run, the similar/same clusters in the next version will maintain the consistent_uuid.
+	ConsistentUUID string `json:"consistent_uuid,omitempty"`
+	// JSON object representation of the query defining the cluster in dynamic mode.
+	Query json.RawMessage `json:"query,omitempty"`
+	// Indicates whether the cluster is approved, approved clusters are kept as is by ADM runs.
+	Approved bool `json:"approved"`
+	// Indicates whether the cluster contains endpoints outside of the application scope.
+	External bool `json:"external"`
+}
+
+// createApplicationRequest wraps parameters for making a request to create
+// an application with clusters that may be defined by queries.
+type createApplicationRequest struct {
+	tetration.CreateApplicationRequest
+	// Groups of nodes to be used to define policies.
+	Clusters []applicationCluster `json:"clusters"`
+}
+
+// updateClusterRequest wraps parameters for making a request to update a cluster.
+type updateClusterRequest struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description"`
+	Nodes       []tetration.Node `json:"nodes,omitempty"`
+	Query       json.RawMessage  `json:"query,omitempty"`
+	Approved    bool             `json:"approved"`
+	External    bool             `json:"external"`
+}
+
+// createApplication creates an application with the specified params, returning
+// the created application.
+func createApplication(apiClient client.Client, params createApplicationRequest) (tetration.Application, error) {
+	var application tetration.Application
+	err := doRequest(apiClient, http.MethodPost, "/applications", params, &application)
+	return application, err
+}
+
+// listApplicationClusters lists the clusters of the latest version of an
+// application, returning the clusters.
+func listApplicationClusters(apiClient client.Client, applicationId string) ([]applicationCluster, error) {
+	var clusters []applicationCluster
+	err := doRequest(apiClient, http.MethodGet, fmt.Sprintf("/applications/%s/clusters", applicationId), nil, &clusters)
+	return clusters, err
+}
+
+// updateCluster updates a cluster by id, returning the updated cluster.
+func updateCluster(apiClient client.Client, clusterId string, params updateClusterRequest) (applicationCluster, error) {
+	var cluster applicationCluster
+	err := doRequest(apiClient, http.MethodPut, fmt.Sprintf("/clusters/%s", clusterId), params, &cluster)
+	return cluster, err
+}
+
+// validateClusterFromTerraform validates that a cluster block is defined by
+// exactly one of nodes, a raw query or a structured query.
+func validateClusterFromTerraform(tf terraformObject) error {
+	definitions := 0
+	if len(tf["node"].([]interface{})) > 0 {
+		definitions++
+	}
+	if tf["query"].(string) != "" {
+		definitions++
+	}
+	if tf["query_type"].(string) != "" || tf["query_field"].(string) != "" || tf["query_value"].(string) != "" {
+		if tf["query_type"].(string) == "" || tf["query_field"].(string) == "" {
+			return errors.New("query_type and query_field must be specified together with query_value")
+		}
+		definitions++
+	}
+	if definitions > 1 {
+		return errors.New("Only one of node, query or query_type/query_field/query_value can be specified")
+	}
+	return nil
+}
+
+// clusterQueryFromTerraform returns the JSON query of a cluster block
+// from either its raw or its structured query, or nil if it has neither.
+func clusterQueryFromTerraform(tf terraformObject) (json.RawMessage, error) {
+	if query := tf["query"].(string); query != "" {
+		return json.RawMessage(query), nil
+	}
+	if tf["query_type"].(string) == "" {
+		return nil, nil
+	}
+	return json.Marshal(tetration.ShortQuery{
+		Type:  tf["query_type"].(string),
+		Field: tf["query_field"].(string),
+		Value: tf["query_value"].(string),
+	})
+}
+
+// clusterToTerraform updates a cluster block with the attributes of the cluster,
+// keeping the query in the raw or structured form the block uses.
+func clusterToTerraform(cluster applicationCluster, tf terraformObject) {
+	tf["cluster_id"] = cluster.Id
+	tf["name"] = cluster.Name
+	tf["description"] = cluster.Description
+	tf["approved"] = cluster.Approved
+	tf["external"] = cluster.External
+	if cluster.ConsistentUUID != "" {
+		tf["consistent_uuid"] = cluster.ConsistentUUID
+	}
+	if len(cluster.Query) == 0 || string(cluster.Query) == "null" {
+		tfNodes := make([]interface{}, 0, len(cluster.Nodes))
+		for _, node := range cluster.Nodes {
+			tfNodes = append(tfNodes, terraformObject{
+				"ip_address": node.IPAddress,
+				"name":       node.Name,
+			})
+		}
+		tf["node"] = tfNodes
+		return
+	}
+	var query tetration.ShortQuery
+	decoder := json.NewDecoder(bytes.NewReader(cluster.Query))
+	decoder.UseNumber()
+	// Blocks using the structured form keep it, so that a query that is no
+	// longer a single condition is reported as a change of its structured attributes
+	// rather than as a raw query the configuration doesn't set
+	if tf["query_type"].(string) != "" && decoder.Decode(&query) == nil {
+		tf["query_type"] = query.Type
+		tf["query_field"] = query.Field
+		tf["query_value"] = clusterQueryValueToString(query.Value)
+		return
+	}
+	tf["query"] = normalizeJSON(string(cluster.Query))
+}
+
+// clusterQueryValueToString returns the value of a single condition query as
+// configured by query_value, formatting numbers and booleans the way they are written.
+func clusterQueryValueToString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		encoded, _ := json.Marshal(v)
+		return string(encoded)
+	}
+}
+
+// assignClusterIds sets the Tetration id of cluster blocks that do not yet
+// have one by matching them against the not yet claimed clusters with the
+// same consistent uuid, or else the same name.
+func assignClusterIds(tfClusters []interface{}, clusters []applicationCluster) {
+	claimed := make(map[string]bool)
+	for _, tfCluster := range tfClusters {
+		if tfCluster != nil && tfCluster.(terraformObject)["cluster_id"].(string) != "" {
+			claimed[tfCluster.(terraformObject)["cluster_id"].(string)] = true
+		}
+	}
+	for _, tfCluster := range tfClusters {
+		if tfCluster == nil {
+			continue
+		}
+		tf := tfCluster.(terraformObject)
+		if tf["cluster_id"].(string) != "" {
+			continue
+		}
+		for _, cluster := range clusters {
+			if claimed[cluster.Id] {
+				continue
+			}
+			matchesUUID := tf["consistent_uuid"].(string) != "" && tf["consistent_uuid"].(string) == cluster.ConsistentUUID
+			matchesName := tf["consistent_uuid"].(string) == "" && tf["name"].(string) == cluster.Name
+			if matchesUUID || matchesName {
+				tf["cluster_id"] = cluster.Id
+				claimed[cluster.Id] = true
+				break
+			}
+		}
+	}
+}
+
+// readApplicationClusters refreshes the cluster blocks of the application from
+// its clusters.
+func readApplicationClusters(apiClient client.Client, d *schema.ResourceData) error {
+	clusters, err := listApplicationClusters(apiClient, d.Id())
+	if err != nil {
+		return err
+	}
+	clustersById := make(map[string]applicationCluster)
+	for _, cluster := range clusters {
+		clustersById[cluster.Id] = cluster
+	}
+	tfClusters := d.Get("cluster").([]interface{})
+	assignClusterIds(tfClusters, clusters)
+	var refreshedTfClusters []interface{}
+	for _, tfCluster := range tfClusters {
+		if tfCluster == nil {
+			continue
+		}
+		tf := tfCluster.(terraformObject)
+		cluster, ok := clustersById[tf["cluster_id"].(string)]
+		// Clusters deleted outside of terraform are dropped from state
+		if !ok {
+			continue
+		}
+		clusterToTerraform(cluster, tf)
+		refreshedTfClusters = append(refreshedTfClusters, tf)
+	}
+	return d.Set("cluster", refreshedTfClusters)
+}
+
+// updateApplicationClusters updates the clusters of the application whose
+// blocks changed in place.
+func updateApplicationClusters(apiClient client.Client, d *schema.ResourceData) error {
+	oldTfClusters, newTfClusters := d.GetChange("cluster")
+	for index, tfCluster := range newTfClusters.([]interface{}) {
+		if tfCluster == nil || index >= len(oldTfClusters.([]interface{})) {
+			continue
+		}
+		tf := tfCluster.(terraformObject)
+		if !d.HasChange(fmt.Sprintf("cluster.%d", index)) {
+			continue
+		}
+		cluster, err := clusterFromTerraform(tf)
+		if err != nil {
+			return err
+		}
+		clusterId := oldTfClusters.([]interface{})[index].(terraformObject)["cluster_id"].(string)
+		if clusterId == "" {
+			return fmt.Errorf("Unable to update cluster %s of application %s without a known cluster id", cluster.Id, d.Id())
+		}
+		_, err = updateCluster(apiClient, clusterId, updateClusterRequest{
+			Name:        cluster.Name,
+			Description: cluster.Description,
+			Nodes:       cluster.Nodes,
+			Query:       cluster.Query,
+			Approved:    cluster.Approved,
+			External:    cluster.External,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// clusterIdentitiesChanged reports whether clusters were added, removed
+// or had their id used by policies changed, which requires recreating the application.
+func clusterIdentitiesChanged(oldTfClusters interface{}, newTfClusters interface{}) bool {
+	oldClusters := oldTfClusters.([]interface{})
+	newClusters := newTfClusters.([]interface{})
+	if len(oldClusters) != len(newClusters) {
+		return true
+	}
+	for index := range newClusters {
+		if oldClusters[index] == nil || newClusters[index] == nil {
+			return true
+		}
+		if oldClusters[index].(terraformObject)["id"] != newClusters[index].(terraformObject)["id"] {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeJSON returns the compact, key sorted encoding of a JSON document,
+// or the document as is if it is not valid JSON.
+func normalizeJSON(document string) string {
+	var value interface{}
+	if err := json.Unmarshal([]byte(document), &value); err != nil {
+		return document
+	}
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return document
+	}
+	return string(bytes.TrimSpace(buffer.Bytes()))
+}
+
+// suppressEquivalentJSONDiffs suppresses diffs between JSON documents that only
+// differ in formatting or key order.
+func suppressEquivalentJSONDiffs(k, old, new string, d *schema.ResourceData) bool {
+	return normalizeJSON(old) == normalizeJSON(new)
+}
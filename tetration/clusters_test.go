@@ -0,0 +1,39 @@
+package tetration
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestClusterToTerraformStructuredQuery(t *testing.T) {
+	for _, test := range []struct {
+		query    string
+		expected [3]string
+	}{
+		{`{"type":"eq","field":"ip","value":"10.0.0.1"}`, [3]string{"eq", "ip", "10.0.0.1"}},
+		{`{"type":"eq","field":"host_uuid_count","value":12345678}`, [3]string{"eq", "host_uuid_count", "12345678"}},
+		{`{"type":"eq","field":"user_enabled","value":true}`, [3]string{"eq", "user_enabled", "true"}},
+		{`{"type":"and","filters":[{"type":"eq","field":"ip","value":"10.0.0.1"}]}`, [3]string{"and", "", ""}},
+	} {
+		tf := terraformObject{"query": "", "query_type": "eq", "query_field": "ip", "query_value": "10.0.0.1"}
+		clusterToTerraform(applicationCluster{Query: json.RawMessage(test.query)}, tf)
+		actual := [3]string{tf["query_type"].(string), tf["query_field"].(string), tf["query_value"].(string)}
+		if actual != test.expected || tf["query"].(string) != "" {
+			t.Errorf("Expected %s to be read as %v, got %v and query %q", test.query, test.expected, actual, tf["query"])
+		}
+		query, err := clusterQueryFromTerraform(tf)
+		if err != nil {
+			t.Errorf("Unexpected error for %s: %s", test.query, err)
+		} else if test.expected[0] == "eq" && normalizeJSON(string(query)) != normalizeJSON(`{"type":"eq","field":"`+test.expected[1]+`","value":"`+test.expected[2]+`"}`) {
+			t.Errorf("Unexpected query %s for %s", query, test.query)
+		}
+	}
+}
+
+func TestClusterToTerraformRawQuery(t *testing.T) {
+	tf := terraformObject{"query": `{"type": "eq", "field": "ip", "value": "10.0.0.1"}`, "query_type": "", "query_field": "", "query_value": ""}
+	clusterToTerraform(applicationCluster{Query: json.RawMessage(`{"value":"10.0.0.1","type":"eq","field":"ip"}`)}, tf)
+	if tf["query"].(string) != `{"field":"ip","type":"eq","value":"10.0.0.1"}` || tf["query_type"].(string) != "" {
+		t.Errorf("Unexpected raw query %q and query type %q", tf["query"], tf["query_type"])
+	}
+}
@@ -0,0 +1,189 @@
+package tetration
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+	client "github.com/tetration-exchange/terraform-go-sdk"
+)
+
+const (
+	IncludeCollectionRuleAction = "INCLUDE"
+	ExcludeCollectionRuleAction = "EXCLUDE"
+)
+
+var (
+	ValidCollectionRuleActions = []string{IncludeCollectionRuleAction, ExcludeCollectionRuleAction}
+)
+
+func resourceTetrationCollectionRule() *schema.Resource {
+	return &schema.Resource{
+		Create:        resourceTetrationCollectionRuleCreate,
+		Update:        resourceTetrationCollectionRuleUpdate,
+		Read:          resourceTetrationCollectionRuleRead,
+		Delete:        resourceTetrationCollectionRuleDelete,
+		CustomizeDiff: resourceTetrationCollectionRuleCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		SchemaVersion: 1,
+
+		Schema: map[string]*schema.Schema{
+			"vrf_id": {
+				Type:        schema.TypeInt,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Numeric ID of the VRF whose collection rules are managed.",
+			},
+			"rule": {
+				Type:        schema.TypeList,
+				Required:    true,
+				MinItems:    1,
+				Description: "Collection rules of the VRF, highest priority first. Flows are collected for addresses whose first matching rule includes them.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"prefix": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validateCollectionRulePrefix,
+							Description:  "Subnet of the rule in CIDR notation, e.g. 10.0.0.0/8. Host bits must be zero.",
+						},
+						"action": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringInSlice(ValidCollectionRuleActions, false),
+							Description:  fmt.Sprintf("Whether flows of the subnet are collected, one of [%s].", strings.Join(ValidCollectionRuleActions, ", ")),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// validateCollectionRulePrefix validates that a prefix is a canonical IPv4 or IPv6 subnet.
+func validateCollectionRulePrefix(v interface{}, k string) ([]string, []error) {
+	prefix := v.(string)
+	_, network, err := net.ParseCIDR(prefix)
+	if err != nil {
+		return nil, []error{fmt.Errorf("%s must be a subnet in CIDR notation, got %q", k, prefix)}
+	}
+	if network.String() != prefix {
+		return nil, []error{fmt.Errorf("%s %q has host bits set or is not canonical, use %s", k, prefix, network)}
+	}
+	return nil, nil
+}
+
+// collectionRule describes whether flows of a subnet are collected.
+type collectionRule struct {
+	Prefix string `json:"prefix"`
+	Action string `json:"action"`
+}
+
+// describeCollectionRules returns the collection rules of a VRF, highest
+// priority first.
+func describeCollectionRules(apiClient client.Client, vrfId int) ([]collectionRule, error) {
+	var rules []collectionRule
+	err := doRequest(apiClient, http.MethodGet, fmt.Sprintf("/vrfs/%d/collection_rules", vrfId), nil, &rules)
+	return rules, err
+}
+
+func updateCollectionRules(apiClient client.Client, vrfId int, rules []collectionRule) error {
+	return doRequest(apiClient, http.MethodPut, fmt.Sprintf("/vrfs/%d/collection_rules", vrfId), rules, nil)
+}
+
+// collectionRulesFromTerraform returns the collection rules of the resource.
+func collectionRulesFromTerraform(tfRules []interface{}) []collectionRule {
+	rules := make([]collectionRule, 0, len(tfRules))
+	for _, tfRule := range tfRules {
+		tf := tfRule.(terraformObject)
+		rules = append(rules, collectionRule{
+			Prefix: tf["prefix"].(string),
+			Action: tf["action"].(string),
+		})
+	}
+	return rules
+}
+
+// resourceTetrationCollectionRuleCreate takes ownership of the collection rules
+// of the VRF, refusing to overwrite rules that exist outside of terraform.
+func resourceTetrationCollectionRuleCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(providerMeta).Client
+	vrfId := d.Get("vrf_id").(int)
+	existing, err := describeCollectionRules(client, vrfId)
+	if err != nil {
+		return err
+	}
+	if len(existing) > 0 {
+		return fmt.Errorf("VRF %d already has %d collection rules, import them with terraform import to manage them", vrfId, len(existing))
+	}
+	return resourceTetrationCollectionRuleUpdate(d, meta)
+}
+
+func resourceTetrationCollectionRuleUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(providerMeta).Client
+	vrfId := d.Get("vrf_id").(int)
+	err := updateCollectionRules(client, vrfId, collectionRulesFromTerraform(d.Get("rule").([]interface{})))
+	if err != nil {
+		return err
+	}
+	d.SetId(strconv.Itoa(vrfId))
+	return resourceTetrationCollectionRuleRead(d, meta)
+}
+
+func resourceTetrationCollectionRuleRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(providerMeta).Client
+	vrfId, err := vrfIdFromResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+	rules, err := describeCollectionRules(client, vrfId)
+	if err != nil {
+		return err
+	}
+	tfRules := make([]interface{}, 0, len(rules))
+	for _, rule := range rules {
+		tfRules = append(tfRules, terraformObject{
+			"prefix": rule.Prefix,
+			"action": rule.Action,
+		})
+	}
+	d.Set("vrf_id", vrfId)
+	d.Set("rule", tfRules)
+	return nil
+}
+
+// resourceTetrationCollectionRuleDelete removes all collection rules of the VRF.
+func resourceTetrationCollectionRuleDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(providerMeta).Client
+	vrfId, err := vrfIdFromResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+	return updateCollectionRules(client, vrfId, []collectionRule{})
+}
+
+// resourceTetrationCollectionRuleCustomizeDiff validates that each prefix has at most one rule,
+// as only the first rule of a prefix would ever match.
+func resourceTetrationCollectionRuleCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("rule") {
+		return nil
+	}
+	seen := make(map[string]bool)
+	for _, rule := range collectionRulesFromTerraform(d.Get("rule").([]interface{})) {
+		if rule.Prefix == "" {
+			continue
+		}
+		if seen[rule.Prefix] {
+			return fmt.Errorf("Prefix %s has more than one collection rule", rule.Prefix)
+		}
+		seen[rule.Prefix] = true
+	}
+	return nil
+}
@@ -0,0 +1,18 @@
+package tetration
+
+import (
+	"testing"
+)
+
+func TestValidateCollectionRulePrefix(t *testing.T) {
+	for _, prefix := range []string{"10.0.0.0/8", "0.0.0.0/0", "192.168.1.1/32", "2001:db8::/32", "::/0"} {
+		if _, errors := validateCollectionRulePrefix(prefix, "prefix"); len(errors) > 0 {
+			t.Errorf("Unexpected errors for %q: %v", prefix, errors)
+		}
+	}
+	for _, prefix := range []string{"", "10.0.0.1", "10.0.0.1/8", "10.0.0.0/33", "2001:DB8::/32", "2001:db8::1/32", "subnet"} {
+		if _, errors := validateCollectionRulePrefix(prefix, "prefix"); len(errors) == 0 {
+			t.Errorf("Expected %q to be invalid", prefix)
+		}
+	}
+}
@@ -0,0 +1,409 @@
+package tetration
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+	client "github.com/tetration-exchange/terraform-go-sdk"
+)
+
+const (
+	ErspanConnectorType     = "erspan"
+	NetflowConnectorType    = "netflow"
+	F5ConnectorType         = "f5"
+	AnyconnectConnectorType = "anyconnect"
+	IseConnectorType        = "ise"
+	ServicenowConnectorType = "servicenow"
+)
+
+var (
+	ValidConnectorTypes = []string{ErspanConnectorType, NetflowConnectorType, F5ConnectorType, AnyconnectConnectorType, IseConnectorType, ServicenowConnectorType}
+	// Type of the virtual appliance each type of connector is deployed on.
+	ConnectorApplianceTypes = map[string]string{
+		ErspanConnectorType:     IngestVirtualApplianceType,
+		NetflowConnectorType:    IngestVirtualApplianceType,
+		F5ConnectorType:         IngestVirtualApplianceType,
+		AnyconnectConnectorType: IngestVirtualApplianceType,
+		IseConnectorType:        EdgeVirtualApplianceType,
+		ServicenowConnectorType: EdgeVirtualApplianceType,
+	}
+)
+
+func resourceTetrationConnector() *schema.Resource {
+	resourceSchema := map[string]*schema.Schema{
+		"appliance_id": {
+			Type:        schema.TypeString,
+			Required:    true,
+			ForceNew:    true,
+			Description: "ID of the virtual appliance the connector is deployed on.",
+		},
+		"name": {
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "User-specified name for the connector.",
+		},
+		"type": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: fmt.Sprintf("Type of the connector, one of [%s].", strings.Join(ValidConnectorTypes, ", ")),
+		},
+		"health": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Health of the connector as reported by its virtual appliance, e.g. HEALTHY or UNHEALTHY, as of the last refresh.",
+		},
+		"health_message": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Details of the health of the connector, such as the reason it is unhealthy.",
+		},
+		"last_heartbeat": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "RFC 3339 timestamp of the last heartbeat of the connector.",
+		},
+	}
+	descriptions := map[string]string{
+		ErspanConnectorType:     "(Optional) ERSPAN connector, generating flows from mirrored traffic sent to the appliance.",
+		NetflowConnectorType:    "(Optional) NetFlow connector, collecting NetFlow v9 and IPFIX records.",
+		F5ConnectorType:         "(Optional) F5 connector, collecting IPFIX records from F5 BIG-IP load balancers.",
+		AnyconnectConnectorType: "(Optional) AnyConnect connector, collecting NVM records from Cisco AnyConnect endpoints.",
+		IseConnectorType:        "(Optional) ISE connector, importing endpoint metadata from Cisco ISE.",
+		ServicenowConnectorType: "(Optional) ServiceNow connector, importing CMDB metadata from a ServiceNow instance.",
+	}
+	for connectorType, configSchema := range connectorConfigSchemas() {
+		resourceSchema[connectorType] = &schema.Schema{
+			Type:          schema.TypeList,
+			Optional:      true,
+			MaxItems:      1,
+			ConflictsWith: connectorTypesExcept(connectorType),
+			Description:   descriptions[connectorType],
+			Elem: &schema.Resource{
+				Schema: configSchema,
+			},
+		}
+	}
+	return &schema.Resource{
+		Create:        resourceTetrationConnectorCreate,
+		Update:        resourceTetrationConnectorUpdate,
+		Read:          resourceTetrationConnectorRead,
+		Delete:        resourceTetrationConnectorDelete,
+		CustomizeDiff: resourceTetrationConnectorCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		SchemaVersion: 1,
+
+		Schema: resourceSchema,
+	}
+}
+
+// connectorConfigSchemas returns the schemas of the configuration blocks of each connector type.
+func connectorConfigSchemas() map[string]map[string]*schema.Schema {
+	vrfSchema := func() *schema.Schema {
+		return &schema.Schema{
+			Type:        schema.TypeInt,
+			Optional:    true,
+			Description: "(Optional) ID of the VRF the collected flows belong to. Defaults to the VRF of the tenant.",
+		}
+	}
+	listenPortSchema := func(port int) *schema.Schema {
+		return &schema.Schema{
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Default:      port,
+			ValidateFunc: validation.IntBetween(1, 65535),
+			Description:  fmt.Sprintf("(Optional) UDP port the connector listens on. Default value is %d.", port),
+		}
+	}
+	return map[string]map[string]*schema.Schema{
+		ErspanConnectorType: {
+			"vrf_id": vrfSchema(),
+		},
+		NetflowConnectorType: {
+			"listen_port": listenPortSchema(4729),
+			"vrf_id":      vrfSchema(),
+		},
+		F5ConnectorType: {
+			"listen_port": listenPortSchema(4739),
+			"vrf_id":      vrfSchema(),
+		},
+		AnyconnectConnectorType: {
+			"listen_port": listenPortSchema(2055),
+			"vrf_id":      vrfSchema(),
+		},
+		IseConnectorType: {
+			"host_names": {
+				Type:     schema.TypeList,
+				Required: true,
+				MinItems: 1,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "Hostnames of the ISE pxGrid nodes.",
+			},
+			"node_name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Name of the pxGrid client registered by the connector.",
+			},
+			"client_certificate": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "PEM encoded client certificate to authenticate to pxGrid with.",
+			},
+			"client_key": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Sensitive:   true,
+				Description: "PEM encoded key of the client certificate. Write-only, never read back.",
+			},
+			"server_ca_certificate": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "PEM encoded CA certificate to verify the certificates of the pxGrid nodes with.",
+			},
+		},
+		ServicenowConnectorType: {
+			"instance_url": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringMatch(regexp.MustCompile("^https://"), "must be an https URL"),
+				Description:  "HTTPS URL of the ServiceNow instance.",
+			},
+			"username": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Username to authenticate with.",
+			},
+			"password": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Sensitive:   true,
+				Description: "Password to authenticate with. Write-only, never read back.",
+			},
+			"table_names": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "(Optional) Names of the CMDB tables to import, e.g. cmdb_ci_server.",
+			},
+		},
+	}
+}
+
+// connectorTypesExcept returns the blocks of all connector types except the given one.
+func connectorTypesExcept(connectorType string) []string {
+	var others []string
+	for _, other := range ValidConnectorTypes {
+		if other != connectorType {
+			others = append(others, other)
+		}
+	}
+	return others
+}
+
+// connector describes a connector deployed on a virtual appliance.
+type connector struct {
+	Id          string `json:"id,omitempty"`
+	Name        string `json:"name"`
+	Type        string `json:"type,omitempty"`
+	ApplianceId string `json:"appliance_id,omitempty"`
+	// Type specific configuration, credentials are omitted from responses.
+	Config        map[string]interface{} `json:"config"`
+	Status        string                 `json:"status,omitempty"`
+	StatusMessage string                 `json:"status_message,omitempty"`
+	// Unix timestamp of the last heartbeat of the connector.
+	LastHeartbeatAt int64 `json:"last_heartbeat_at,omitempty"`
+}
+
+// createConnector deploys a connector, returning the created connector.
+func createConnector(apiClient client.Client, params connector) (connector, error) {
+	var created connector
+	err := doRequest(apiClient, http.MethodPost, "/connectors", params, &created)
+	return created, err
+}
+
+func describeConnector(apiClient client.Client, connectorId string) (connector, error) {
+	var described connector
+	err := doRequest(apiClient, http.MethodGet, fmt.Sprintf("/connectors/%s", connectorId), nil, &described)
+	return described, err
+}
+
+func updateConnector(apiClient client.Client, connectorId string, params connector) error {
+	return doRequest(apiClient, http.MethodPut, fmt.Sprintf("/connectors/%s", connectorId), params, nil)
+}
+
+func deleteConnector(apiClient client.Client, connectorId string) error {
+	return doRequest(apiClient, http.MethodDelete, fmt.Sprintf("/connectors/%s", connectorId), nil, nil)
+}
+
+// connectorTypeFromTerraform returns the type of the single connector type
+// block that is set, failing if none or several are set.
+func connectorTypeFromTerraform(get func(string) interface{}) (string, error) {
+	var types []string
+	for _, connectorType := range ValidConnectorTypes {
+		if len(get(connectorType).([]interface{})) > 0 {
+			types = append(types, connectorType)
+		}
+	}
+	if len(types) != 1 {
+		return "", fmt.Errorf("Exactly one of the [%s] blocks must be specified", strings.Join(ValidConnectorTypes, ", "))
+	}
+	return types[0], nil
+}
+
+// connectorFromTerraform returns the connector configured by the resource, including its credentials.
+func connectorFromTerraform(d *schema.ResourceData) (connector, error) {
+	connectorType, err := connectorTypeFromTerraform(d.Get)
+	if err != nil {
+		return connector{}, err
+	}
+	// Only send the keys that are set, so that Tetration applies its own
+	// defaults instead of the zero values of unset attributes
+	config := terraformObject{}
+	for key := range connectorConfigSchemas()[connectorType] {
+		if value, ok := d.GetOk(fmt.Sprintf("%s.0.%s", connectorType, key)); ok {
+			config[key] = value
+		}
+	}
+	return connector{
+		Name:        d.Get("name").(string),
+		Type:        strings.ToUpper(connectorType),
+		ApplianceId: d.Get("appliance_id").(string),
+		Config:      config,
+	}, nil
+}
+
+// connectorConfigToTerraform returns the configuration of a connector as a
+// terraform object of the given schema. Write-only values are kept from the
+// previous terraform object, as they are never returned by Tetration.
+func connectorConfigToTerraform(config map[string]interface{}, configSchema map[string]*schema.Schema, previous terraformObject) terraformObject {
+	tf := terraformObject{}
+	for key, valueSchema := range configSchema {
+		if valueSchema.Sensitive {
+			if previous != nil {
+				tf[key] = previous[key]
+			}
+			continue
+		}
+		value, ok := config[key]
+		if !ok || value == nil {
+			continue
+		}
+		switch valueSchema.Type {
+		case schema.TypeInt:
+			if number, ok := value.(float64); ok {
+				tf[key] = int(number)
+			}
+		case schema.TypeList:
+			if values, ok := value.([]interface{}); ok {
+				tf[key] = values
+			}
+		default:
+			tf[key] = value
+		}
+	}
+	return tf
+}
+
+func resourceTetrationConnectorCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(providerMeta).Client
+	params, err := connectorFromTerraform(d)
+	if err != nil {
+		return err
+	}
+	appliance, err := describeVirtualAppliance(client, params.ApplianceId)
+	if err != nil {
+		return err
+	}
+	connectorType := strings.ToLower(params.Type)
+	if applianceType := ConnectorApplianceTypes[connectorType]; appliance.Type != applianceType {
+		return fmt.Errorf("%s connectors must be deployed on %s virtual appliances, %s (%s) is a %s virtual appliance", connectorType, applianceType, appliance.Name, appliance.Id, appliance.Type)
+	}
+	created, err := createConnector(client, params)
+	if err != nil {
+		return err
+	}
+	d.SetId(created.Id)
+	return resourceTetrationConnectorRead(d, meta)
+}
+
+func resourceTetrationConnectorUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(providerMeta).Client
+	params, err := connectorFromTerraform(d)
+	if err != nil {
+		return err
+	}
+	err = updateConnector(client, d.Id(), params)
+	if err != nil {
+		return err
+	}
+	return resourceTetrationConnectorRead(d, meta)
+}
+
+func resourceTetrationConnectorRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(providerMeta).Client
+	described, err := describeConnector(client, d.Id())
+	if err != nil {
+		return err
+	}
+	connectorType := strings.ToLower(described.Type)
+	configSchema, ok := connectorConfigSchemas()[connectorType]
+	if !ok {
+		return fmt.Errorf("Connector %s has unsupported type %s", d.Id(), described.Type)
+	}
+	var previous terraformObject
+	if tfConfigs := d.Get(connectorType).([]interface{}); len(tfConfigs) > 0 {
+		previous, _ = tfConfigs[0].(terraformObject)
+	}
+	lastHeartbeat := ""
+	if described.LastHeartbeatAt > 0 {
+		lastHeartbeat = time.Unix(described.LastHeartbeatAt, 0).UTC().Format(time.RFC3339)
+	}
+	d.Set("appliance_id", described.ApplianceId)
+	d.Set("name", described.Name)
+	d.Set("type", connectorType)
+	d.Set(connectorType, []interface{}{connectorConfigToTerraform(described.Config, configSchema, previous)})
+	d.Set("health", described.Status)
+	d.Set("health_message", described.StatusMessage)
+	d.Set("last_heartbeat", lastHeartbeat)
+	return nil
+}
+
+func resourceTetrationConnectorDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(providerMeta).Client
+	return deleteConnector(client, d.Id())
+}
+
+// resourceTetrationConnectorCustomizeDiff validates that exactly one connector
+// type block is set and replaces the connector when its type changes.
+func resourceTetrationConnectorCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	for _, connectorType := range ValidConnectorTypes {
+		if !d.NewValueKnown(connectorType) {
+			return nil
+		}
+	}
+	connectorType, err := connectorTypeFromTerraform(d.Get)
+	if err != nil {
+		return err
+	}
+	if d.Id() == "" {
+		return d.SetNew("type", connectorType)
+	}
+	if d.Get("type").(string) != connectorType {
+		if err := d.SetNew("type", connectorType); err != nil {
+			return err
+		}
+		return d.ForceNew("type")
+	}
+	return nil
+}
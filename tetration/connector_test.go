@@ -0,0 +1,79 @@
+package tetration
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/configs/hcl2shim"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestConnectorFromTerraformOnlySendsSetKeys(t *testing.T) {
+	for _, test := range []struct {
+		raw      map[string]interface{}
+		expected terraformObject
+	}{
+		{
+			raw: map[string]interface{}{
+				"netflow": []interface{}{map[string]interface{}{}},
+			},
+			expected: terraformObject{"listen_port": 4729},
+		},
+		{
+			raw: map[string]interface{}{
+				"netflow": []interface{}{map[string]interface{}{"listen_port": 9995, "vrf_id": 3}},
+			},
+			expected: terraformObject{"listen_port": 9995, "vrf_id": 3},
+		},
+		{
+			raw: map[string]interface{}{
+				"erspan": []interface{}{map[string]interface{}{}},
+			},
+			expected: terraformObject{},
+		},
+	} {
+		test.raw["name"] = "connector"
+		test.raw["appliance_id"] = "appliance"
+		d := schema.TestResourceDataRaw(t, resourceTetrationConnector().Schema, test.raw)
+		params, err := connectorFromTerraform(d)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if len(params.Config) != len(test.expected) {
+			t.Errorf("Expected config %v, got %v", test.expected, params.Config)
+		}
+		for key, value := range test.expected {
+			if params.Config[key] != value {
+				t.Errorf("Expected %s to be %v, got %v", key, value, params.Config[key])
+			}
+		}
+	}
+}
+
+func TestVirtualApplianceCustomizeDiffPrefixLength(t *testing.T) {
+	for _, test := range []struct {
+		applianceInterface map[string]interface{}
+		expected           string
+	}{
+		{map[string]interface{}{"ip": "10.0.0.5", "prefix_length": 24, "gateway": "10.0.0.1"}, ""},
+		{map[string]interface{}{"ip": "2001:db8::5", "prefix_length": 64}, ""},
+		{map[string]interface{}{"ip": "10.0.0.5", "prefix_length": 64}, "interface.0: Invalid prefix length 64 of IPv4 interface 10.0.0.5"},
+		{map[string]interface{}{"ip": "10.0.0.5", "prefix_length": 24, "gateway": "10.0.1.1"}, "interface.0: Gateway 10.0.1.1 is not in the network"},
+		{map[string]interface{}{"ip": hcl2shim.UnknownVariableValue, "prefix_length": 64}, ""},
+	} {
+		config := terraform.NewResourceConfigRaw(map[string]interface{}{
+			"root_app_scope_id": "scope",
+			"name":              "appliance",
+			"type":              "TETRATION_INGEST",
+			"interface":         []interface{}{test.applianceInterface},
+		})
+		_, err := resourceTetrationVirtualAppliance().Diff(nil, config, providerMeta{})
+		if test.expected == "" && err != nil {
+			t.Errorf("Expected no error for %v, got %s", test.applianceInterface, err)
+		}
+		if test.expected != "" && (err == nil || !strings.Contains(err.Error(), test.expected)) {
+			t.Errorf("Expected error %q for %v, got %v", test.expected, test.applianceInterface, err)
+		}
+	}
+}
@@ -0,0 +1,286 @@
+package tetration
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+	client "github.com/tetration-exchange/terraform-go-sdk"
+)
+
+const (
+	PlainSaslMechanism       = "PLAIN"
+	ScramSha256SaslMechanism = "SCRAM-SHA-256"
+	ScramSha512SaslMechanism = "SCRAM-SHA-512"
+)
+
+var (
+	ValidSaslMechanisms = []string{PlainSaslMechanism, ScramSha256SaslMechanism, ScramSha512SaslMechanism}
+)
+
+func resourceTetrationDatatap() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceTetrationDatatapCreate,
+		Update: resourceTetrationDatatapUpdate,
+		Read:   resourceTetrationDatatapRead,
+		Delete: resourceTetrationDatatapDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		SchemaVersion: 1,
+
+		Schema: map[string]*schema.Schema{
+			"root_app_scope_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "ID of the root scope the data tap belongs to.",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "User-specified name for the data tap.",
+			},
+			"brokers": {
+				Type:     schema.TypeList,
+				Required: true,
+				MinItems: 1,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateBrokerAddress,
+				},
+				Description: "Addresses of the Kafka brokers, as host:port.",
+			},
+			"topic": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Kafka topic messages are published to.",
+			},
+			"tls": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: "(Optional) TLS settings of the connections to the brokers. Connections are not encrypted if not specified.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"ca_certificate": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "PEM encoded CA certificates to verify the certificates of the brokers with.",
+						},
+						"client_certificate": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "(Optional) PEM encoded client certificate to authenticate to the brokers with.",
+						},
+						"client_key": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Sensitive:   true,
+							Description: "(Optional) PEM encoded key of the client certificate. Write-only, never read back.",
+						},
+						"insecure": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+							Description: "(Optional) Skip the verification of the hostnames of the certificates of the brokers. Default value is false.",
+						},
+					},
+				},
+			},
+			"sasl": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: "(Optional) SASL authentication to the brokers.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"mechanism": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      PlainSaslMechanism,
+							ValidateFunc: validation.StringInSlice(ValidSaslMechanisms, false),
+							Description:  fmt.Sprintf("(Optional) SASL mechanism, one of [%s]. Default value is %s.", strings.Join(ValidSaslMechanisms, ", "), PlainSaslMechanism),
+						},
+						"username": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "Username to authenticate with.",
+						},
+						"password": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Sensitive:   true,
+							Description: "Password to authenticate with. Write-only, never read back.",
+						},
+					},
+				},
+			},
+			"connection_status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Status of the connection of Tetration to the brokers, as of the last refresh.",
+			},
+			"connection_error": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Error of the connection of Tetration to the brokers, empty if connected.",
+			},
+		},
+	}
+}
+
+// validateBrokerAddress validates that a Kafka broker address is of the form host:port.
+func validateBrokerAddress(v interface{}, k string) ([]string, []error) {
+	address := v.(string)
+	host, port, err := net.SplitHostPort(address)
+	if err != nil || host == "" {
+		return nil, []error{fmt.Errorf("%s must be of the form host:port, got %q", k, address)}
+	}
+	if number, err := strconv.Atoi(port); err != nil || number < 1 || number > 65535 {
+		return nil, []error{fmt.Errorf("%s has invalid port %q", k, port)}
+	}
+	return nil, nil
+}
+
+// datatap describes a Kafka cluster Tetration publishes alerts and flows to.
+// Credentials are write-only and omitted from responses.
+type datatap struct {
+	Id                string   `json:"id,omitempty"`
+	RootAppScopeId    string   `json:"root_app_scope_id,omitempty"`
+	Name              string   `json:"name"`
+	Type              string   `json:"type"`
+	Brokers           []string `json:"brokers"`
+	Topic             string   `json:"topic"`
+	SecureConnection  bool     `json:"secure_connection"`
+	CaCertificate     string   `json:"ca_certificate,omitempty"`
+	ClientCertificate string   `json:"client_certificate,omitempty"`
+	ClientKey         string   `json:"client_key,omitempty"`
+	Insecure          bool     `json:"insecure"`
+	SaslMechanism     string   `json:"sasl_mechanism,omitempty"`
+	SaslUsername      string   `json:"sasl_username,omitempty"`
+	SaslPassword      string   `json:"sasl_password,omitempty"`
+	// Status of the connection to the brokers, e.g. “Success” or “Failure”.
+	ConnectionStatus string `json:"connection_status,omitempty"`
+	// Reason of the last connection failure.
+	FailureReason string `json:"failure_reason,omitempty"`
+}
+
+// createDatatap creates a data tap, returning the created data tap.
+func createDatatap(apiClient client.Client, params datatap) (datatap, error) {
+	var created datatap
+	err := doRequest(apiClient, http.MethodPost, "/datataps", params, &created)
+	return created, err
+}
+
+func describeDatatap(apiClient client.Client, datatapId string) (datatap, error) {
+	var described datatap
+	err := doRequest(apiClient, http.MethodGet, fmt.Sprintf("/datataps/%s", datatapId), nil, &described)
+	return described, err
+}
+
+// updateDatatap updates a data tap, leaving omitted credentials unchanged.
+func updateDatatap(apiClient client.Client, datatapId string, params datatap) error {
+	return doRequest(apiClient, http.MethodPut, fmt.Sprintf("/datataps/%s", datatapId), params, nil)
+}
+
+func deleteDatatap(apiClient client.Client, datatapId string) error {
+	return doRequest(apiClient, http.MethodDelete, fmt.Sprintf("/datataps/%s", datatapId), nil, nil)
+}
+
+// datatapFromTerraform returns the data tap configured by the resource, including its credentials.
+func datatapFromTerraform(d *schema.ResourceData) datatap {
+	params := datatap{
+		RootAppScopeId: d.Get("root_app_scope_id").(string),
+		Name:           d.Get("name").(string),
+		Type:           "kafka",
+		Brokers:        stringsFromTerraform(d.Get("brokers").([]interface{})),
+		Topic:          d.Get("topic").(string),
+	}
+	if tfTls := d.Get("tls").([]interface{}); len(tfTls) > 0 {
+		tf := tfTls[0].(terraformObject)
+		params.SecureConnection = true
+		params.CaCertificate = tf["ca_certificate"].(string)
+		params.ClientCertificate = tf["client_certificate"].(string)
+		params.ClientKey = tf["client_key"].(string)
+		params.Insecure = tf["insecure"].(bool)
+	}
+	if tfSasl := d.Get("sasl").([]interface{}); len(tfSasl) > 0 {
+		tf := tfSasl[0].(terraformObject)
+		params.SaslMechanism = tf["mechanism"].(string)
+		params.SaslUsername = tf["username"].(string)
+		params.SaslPassword = tf["password"].(string)
+	}
+	return params
+}
+
+func resourceTetrationDatatapCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(providerMeta).Client
+	created, err := createDatatap(client, datatapFromTerraform(d))
+	if err != nil {
+		return err
+	}
+	d.SetId(created.Id)
+	return resourceTetrationDatatapRead(d, meta)
+}
+
+func resourceTetrationDatatapUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(providerMeta).Client
+	err := updateDatatap(client, d.Id(), datatapFromTerraform(d))
+	if err != nil {
+		return err
+	}
+	return resourceTetrationDatatapRead(d, meta)
+}
+
+func resourceTetrationDatatapRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(providerMeta).Client
+	described, err := describeDatatap(client, d.Id())
+	if err != nil {
+		return err
+	}
+	tfTls := []interface{}{}
+	if described.SecureConnection {
+		clientKey := ""
+		if previous := d.Get("tls").([]interface{}); len(previous) > 0 && previous[0] != nil {
+			clientKey = previous[0].(terraformObject)["client_key"].(string)
+		}
+		tfTls = append(tfTls, terraformObject{
+			"ca_certificate":     described.CaCertificate,
+			"client_certificate": described.ClientCertificate,
+			"client_key":         clientKey,
+			"insecure":           described.Insecure,
+		})
+	}
+	tfSasl := []interface{}{}
+	if described.SaslUsername != "" {
+		password := ""
+		if previous := d.Get("sasl").([]interface{}); len(previous) > 0 && previous[0] != nil {
+			password = previous[0].(terraformObject)["password"].(string)
+		}
+		tfSasl = append(tfSasl, terraformObject{
+			"mechanism": described.SaslMechanism,
+			"username":  described.SaslUsername,
+			"password":  password,
+		})
+	}
+	d.Set("root_app_scope_id", described.RootAppScopeId)
+	d.Set("name", described.Name)
+	d.Set("brokers", described.Brokers)
+	d.Set("topic", described.Topic)
+	d.Set("tls", tfTls)
+	d.Set("sasl", tfSasl)
+	d.Set("connection_status", described.ConnectionStatus)
+	d.Set("connection_error", described.FailureReason)
+	return nil
+}
+
+func resourceTetrationDatatapDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(providerMeta).Client
+	return deleteDatatap(client, d.Id())
+}
@@ -1,10 +1,10 @@
 package tetration
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"github.com/hashicorp/terraform/helper/schema"
-	client "github.com/tetration-exchange/terraform-go-sdk"
 	tetration "github.com/tetration-exchange/terraform-go-sdk"
 )
 
@@ -52,6 +52,20 @@ func resourceTetrationFilter() *schema.Resource {
 				Default:     false,
 				Description: "(Optional) When true the filter provides a service for its scope. Must also be primary/scope restricted.",
 			},
+			"vrf_id": {
+				Type:          schema.TypeInt,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"vrf_name"},
+				Description:   "(Optional) ID of the VRF to restrict the query of the filter to.",
+			},
+			"vrf_name": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"vrf_id"},
+				Description:   "(Optional) Name of the VRF to restrict the query of the filter to, instead of its vrf_id.",
+			},
 		},
 	}
 }
@@ -59,16 +73,27 @@ func resourceTetrationFilter() *schema.Resource {
 var requiredCreateFilterParams = []string{"name", "app_scope_id", "query"}
 
 func resourceTetrationFilterCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(client.Client)
+	client := meta.(providerMeta).Client
 	for _, param := range requiredCreateFilterParams {
 		if d.Get(param) == "" {
 			return fmt.Errorf("%s is required but was not provided", param)
 		}
 	}
+	query := json.RawMessage(d.Get("query").(string))
+	vrfId, err := vrfIdFromTerraform(client, d)
+	if err != nil {
+		return err
+	}
+	if vrfId != 0 {
+		query, err = vrfRestrictedQuery(query, vrfId)
+		if err != nil {
+			return err
+		}
+	}
 	createFilterParams := tetration.CreateFilterRequest{
 		Name:       d.Get("name").(string),
 		AppScopeId: d.Get("app_scope_id").(string),
-		Query:      []byte(d.Get("query").(string)),
+		Query:      query,
 		Primary:    d.Get("primary").(bool),
 		Public:     d.Get("public").(bool),
 	}
@@ -80,8 +105,20 @@ func resourceTetrationFilterCreate(d *schema.ResourceData, meta interface{}) err
 	return nil
 }
 
+// vrfRestrictedQuery returns an inventory filter query matching the items
+// of the given VRF that match query.
+func vrfRestrictedQuery(query json.RawMessage, vrfId int) (json.RawMessage, error) {
+	return json.Marshal(map[string]interface{}{
+		"type": "and",
+		"filters": []interface{}{
+			map[string]interface{}{"type": "eq", "field": "vrf_id", "value": vrfId},
+			query,
+		},
+	})
+}
+
 func resourceTetrationFilterRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(client.Client)
+	client := meta.(providerMeta).Client
 	filter, err := client.DescribeFilter(d.Id())
 	if err != nil {
 		return err
@@ -94,6 +131,6 @@ func resourceTetrationFilterRead(d *schema.ResourceData, meta interface{}) error
 }
 
 func resourceTetrationFilterDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(client.Client)
+	client := meta.(providerMeta).Client
 	return client.DeleteFilter(d.Id())
 }
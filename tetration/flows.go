@@ -0,0 +1,254 @@
+package tetration
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+	client "github.com/tetration-exchange/terraform-go-sdk"
+)
+
+const (
+	// Maximum number of flows to request per page of flow search results.
+	FlowSearchPageSize = 1000
+	// Maximum number of flows retrieved when searching for all matching flows.
+	MaxFlowSearchResults = 100000
+)
+
+var (
+	// Metrics returned for each flow when no metrics are requested.
+	DefaultFlowMetrics = []string{"fwd_pkts", "rev_pkts", "fwd_bytes", "rev_bytes", "srtt_usec", "server_app_latency_usec", "total_network_latency_usec"}
+)
+
+func dataSourceTetrationFlows() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceTetrationFlowsRead,
+
+		Schema: map[string]*schema.Schema{
+			"scope_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "ID of the scope to search the flows of.",
+			},
+			"start_time": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.ValidateRFC3339TimeString,
+				Description:  "RFC 3339 timestamp of the start of the time window to search flows in.",
+			},
+			"end_time": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.ValidateRFC3339TimeString,
+				Description:  "RFC 3339 timestamp of the end of the time window to search flows in.",
+			},
+			"filter": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.ValidateJsonString,
+				Description:  "(Optional) JSON object representation of a flow filter query, such as {\"type\": \"eq\", \"field\": \"dst_port\", \"value\": \"443\"}; defaults to all flows.",
+			},
+			"dimensions": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "(Optional) Flow dimensions to return for each flow, such as src_address or dst_port; defaults to all dimensions.",
+			},
+			"metrics": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "(Optional) Flow metrics to return for each flow, such as fwd_pkts or rev_bytes; defaults to all metrics.",
+			},
+			"offset": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "(Optional) Offset to continue a previous search from, as returned by its next_offset.",
+			},
+			"max_results": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      FlowSearchPageSize,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  fmt.Sprintf("(Optional) Maximum number of flows to return, 0 for all matching flows (at most %d). Default value is %d.", MaxFlowSearchResults, FlowSearchPageSize),
+			},
+			"flow": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Flows matching the filter.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"dimensions": {
+							Type:     schema.TypeMap,
+							Computed: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+							Description: "Dimensions of the flow, keyed by dimension name.",
+						},
+						"metrics": {
+							Type:     schema.TypeMap,
+							Computed: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+							Description: "Metrics of the flow, keyed by metric name.",
+						},
+					},
+				},
+			},
+			"flow_count": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Number of flows returned.",
+			},
+			"metric_totals": {
+				Type:     schema.TypeMap,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "Sum of each numeric metric over the returned flows, keyed by metric name.",
+			},
+			"next_offset": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Offset to request the next flows with, empty if all matching flows were returned.",
+			},
+		},
+	}
+}
+
+// flowSearchRequest wraps parameters for making a request to search
+// the flows observed in a time window.
+type flowSearchRequest struct {
+	StartTime string `json:"t0"`
+	EndTime   string `json:"t1"`
+	// (Optional) Flow filter query; defaults to all flows.
+	Filter    json.RawMessage `json:"filter,omitempty"`
+	ScopeName string          `json:"scopeName"`
+	// (Optional) Dimensions to return for each flow; defaults to all.
+	Dimensions []string `json:"dimensions,omitempty"`
+	// (Optional) Metrics to return for each flow; defaults to all.
+	Metrics []string `json:"metrics,omitempty"`
+	Limit   int      `json:"limit"`
+	Offset  string   `json:"offset,omitempty"`
+}
+
+// flowSearchResponse wraps a page of flow search results.
+type flowSearchResponse struct {
+	// Offset to request the next page of results with, empty for the last page.
+	Offset  string                   `json:"offset"`
+	Results []map[string]interface{} `json:"results"`
+}
+
+// searchFlows retrieves pages of the flows matching the search until maxResults
+// flows were found, returning the flows and the offset of the next page (empty
+// for the last page). If maxResults is 0 all flows are retrieved, up to
+// MaxFlowSearchResults.
+func searchFlows(apiClient client.Client, params flowSearchRequest, maxResults int) ([]map[string]interface{}, string, error) {
+	var flows []map[string]interface{}
+	params.Limit = FlowSearchPageSize
+	for {
+		if maxResults > 0 && maxResults-len(flows) < params.Limit {
+			params.Limit = maxResults - len(flows)
+		}
+		var page flowSearchResponse
+		err := doRequest(apiClient, http.MethodPost, "/flowsearch", params, &page)
+		if err != nil {
+			return flows, "", err
+		}
+		flows = append(flows, page.Results...)
+		if page.Offset == "" || len(page.Results) == 0 {
+			return flows, "", nil
+		}
+		if maxResults > 0 && len(flows) >= maxResults {
+			return flows, page.Offset, nil
+		}
+		if maxResults == 0 && len(flows) >= MaxFlowSearchResults {
+			return flows, "", fmt.Errorf("Flow search matched more than %d flows, use a more specific filter or a smaller time window", MaxFlowSearchResults)
+		}
+		if page.Offset == params.Offset {
+			return flows, "", fmt.Errorf("Flow search returned the same offset %q twice", page.Offset)
+		}
+		params.Offset = page.Offset
+	}
+}
+
+// flowToTerraform splits a flow into its requested (or default) metrics and its
+// other dimensions as a terraform flow object, adding numeric metrics to totals.
+func flowToTerraform(flow map[string]interface{}, metrics []string, totals map[string]float64) terraformObject {
+	if len(metrics) == 0 {
+		metrics = DefaultFlowMetrics
+	}
+	isMetric := make(map[string]bool)
+	for _, metric := range metrics {
+		isMetric[metric] = true
+	}
+	dimensions := make(map[string]string)
+	metricValues := make(map[string]string)
+	for name, value := range inventoryItemToStrings(flow) {
+		if isMetric[name] {
+			metricValues[name] = value
+			if number, err := strconv.ParseFloat(value, 64); err == nil {
+				totals[name] += number
+			}
+			continue
+		}
+		dimensions[name] = value
+	}
+	return terraformObject{
+		"dimensions": dimensions,
+		"metrics":    metricValues,
+	}
+}
+
+func dataSourceTetrationFlowsRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(providerMeta).Client
+	scopeId := d.Get("scope_id").(string)
+	scope, err := client.DescribeScope(scopeId)
+	if err != nil {
+		return fmt.Errorf("Unable to describe scope %s: %s", scopeId, err)
+	}
+	params := flowSearchRequest{
+		StartTime:  d.Get("start_time").(string),
+		EndTime:    d.Get("end_time").(string),
+		ScopeName:  scope.Name,
+		Dimensions: stringsFromTerraform(d.Get("dimensions").([]interface{})),
+		Metrics:    stringsFromTerraform(d.Get("metrics").([]interface{})),
+		Offset:     d.Get("offset").(string),
+	}
+	filter := d.Get("filter").(string)
+	if filter != "" {
+		params.Filter = json.RawMessage(filter)
+	}
+	flows, nextOffset, err := searchFlows(client, params, d.Get("max_results").(int))
+	if err != nil {
+		return err
+	}
+	totals := make(map[string]float64)
+	tfFlows := make([]interface{}, 0, len(flows))
+	for _, flow := range flows {
+		tfFlows = append(tfFlows, flowToTerraform(flow, params.Metrics, totals))
+	}
+	metricTotals := make(map[string]string)
+	for metric, total := range totals {
+		metricTotals[metric] = strconv.FormatFloat(total, 'f', -1, 64)
+	}
+	d.Set("flow", tfFlows)
+	d.Set("flow_count", len(tfFlows))
+	d.Set("metric_totals", metricTotals)
+	d.Set("next_offset", nextOffset)
+	d.SetId(fmt.Sprintf("%s:%s:%s:%x", scopeId, params.StartTime, params.EndTime,
+		sha256.Sum256([]byte(normalizeJSON(filter)+strings.Join(params.Dimensions, ",")+strings.Join(params.Metrics, ",")+params.Offset))))
+	return nil
+}
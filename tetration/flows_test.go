@@ -0,0 +1,39 @@
+package tetration
+
+import (
+	"testing"
+)
+
+func TestSearchFlows(t *testing.T) {
+	apiClient, closeServer := testSearchClient(t, "/flowsearch", map[string]interface{}{
+		"":  flowSearchResponse{Offset: "2", Results: []map[string]interface{}{{"dst_port": 443}, {"dst_port": 80}}},
+		"2": flowSearchResponse{Offset: "3", Results: []map[string]interface{}{{"dst_port": 22}}},
+		"3": flowSearchResponse{Results: []map[string]interface{}{{"dst_port": 53}}},
+	})
+	defer closeServer()
+	flows, nextOffset, err := searchFlows(apiClient, flowSearchRequest{}, 0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if len(flows) != 4 || nextOffset != "" {
+		t.Errorf("Expected 4 flows and no next offset, got %v and %q", flows, nextOffset)
+	}
+	flows, nextOffset, err = searchFlows(apiClient, flowSearchRequest{}, 3)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if len(flows) != 3 || nextOffset != "3" {
+		t.Errorf("Expected 3 flows and next offset 3, got %v and %q", flows, nextOffset)
+	}
+}
+
+func TestSearchFlowsStopsOnRepeatedOffset(t *testing.T) {
+	apiClient, closeServer := testSearchClient(t, "/flowsearch", map[string]interface{}{
+		"":  flowSearchResponse{Offset: "2", Results: []map[string]interface{}{{"dst_port": 443}}},
+		"2": flowSearchResponse{Offset: "2", Results: []map[string]interface{}{{"dst_port": 80}}},
+	})
+	defer closeServer()
+	if _, _, err := searchFlows(apiClient, flowSearchRequest{}, 0); err == nil {
+		t.Errorf("Expected an error for a repeated offset")
+	}
+}
@@ -0,0 +1,341 @@
+package tetration
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+	client "github.com/tetration-exchange/terraform-go-sdk"
+)
+
+const (
+	AlertForensicAction  = "alert"
+	ReportForensicAction = "report"
+	// API paths of forensic rules, profiles and intents.
+	forensicRulesPath    = "/forensic_rules"
+	forensicProfilesPath = "/forensic_profiles"
+	forensicIntentsPath  = "/forensic_intents"
+)
+
+var (
+	ValidForensicActions = []string{AlertForensicAction, ReportForensicAction}
+)
+
+func resourceTetrationForensicRule() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceTetrationForensicRuleCreate,
+		Update: resourceTetrationForensicRuleUpdate,
+		Read:   resourceTetrationForensicRuleRead,
+		Delete: resourceTetrationForensicRuleDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		SchemaVersion: 1,
+
+		Schema: map[string]*schema.Schema{
+			"root_app_scope_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "ID of the root scope the forensic rule belongs to.",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "User-specified name for the forensic rule.",
+			},
+			"description": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "(Optional) User-specified description of the forensic rule.",
+			},
+			"expression": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateForensicExpression,
+				Description:  "Expression matching the forensic events of the rule, e.g. `ProcessInfo.exePath = \"/bin/sh\" AND ProcessInfo.uid = 0`.",
+			},
+			"severity": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringInSlice(ValidAlertSeverities, false),
+				Description:  fmt.Sprintf("Severity of the events matching the rule, one of [%s].", strings.Join(ValidAlertSeverities, ", ")),
+			},
+			"actions": {
+				Type:     schema.TypeList,
+				Required: true,
+				MinItems: 1,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringInSlice(ValidForensicActions, false),
+				},
+				Description: fmt.Sprintf("Actions taken on events matching the rule, any of [%s].", strings.Join(ValidForensicActions, ", ")),
+			},
+		},
+	}
+}
+
+func resourceTetrationForensicProfile() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceTetrationForensicProfileCreate,
+		Update: resourceTetrationForensicProfileUpdate,
+		Read:   resourceTetrationForensicProfileRead,
+		Delete: resourceTetrationForensicProfileDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		SchemaVersion: 1,
+
+		Schema: map[string]*schema.Schema{
+			"root_app_scope_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "ID of the root scope the forensic profile belongs to.",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "User-specified name for the forensic profile.",
+			},
+			"rule_ids": {
+				Type:     schema.TypeList,
+				Required: true,
+				MinItems: 1,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "IDs of the forensic rules of the profile.",
+			},
+		},
+	}
+}
+
+func resourceTetrationForensicIntent() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceTetrationForensicIntentCreate,
+		Update: resourceTetrationForensicIntentUpdate,
+		Read:   resourceTetrationForensicIntentRead,
+		Delete: resourceTetrationForensicIntentDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		SchemaVersion: 1,
+
+		Schema: map[string]*schema.Schema{
+			"filter_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "ID of the scope or inventory filter matching the workloads the profiles apply to.",
+			},
+			"profile_ids": {
+				Type:     schema.TypeList,
+				Required: true,
+				MinItems: 1,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "IDs of the forensic profiles applied to the matching workloads.",
+			},
+		},
+	}
+}
+
+// forensicRule describes an expression matching forensic events and the actions taken on them.
+type forensicRule struct {
+	Id             string   `json:"id,omitempty"`
+	RootAppScopeId string   `json:"root_app_scope_id,omitempty"`
+	Name           string   `json:"name"`
+	Description    string   `json:"description"`
+	Expression     string   `json:"clause"`
+	Severity       string   `json:"severity"`
+	Actions        []string `json:"actions"`
+}
+
+// forensicProfile describes a group of forensic rules.
+type forensicProfile struct {
+	Id             string   `json:"id,omitempty"`
+	RootAppScopeId string   `json:"root_app_scope_id,omitempty"`
+	Name           string   `json:"name"`
+	RuleIds        []string `json:"rule_ids"`
+}
+
+// forensicIntent describes the application of forensic profiles to the workloads matching an inventory filter.
+type forensicIntent struct {
+	Id         string   `json:"id,omitempty"`
+	FilterId   string   `json:"inventory_filter_id"`
+	ProfileIds []string `json:"forensic_profile_ids"`
+}
+
+// createForensicObject creates a forensic rule, profile or intent at the given
+// path, decoding the created object into result.
+func createForensicObject(apiClient client.Client, path string, params interface{}, result interface{}) error {
+	return doRequest(apiClient, http.MethodPost, path, params, result)
+}
+
+// describeForensicObject decodes the forensic rule, profile or intent with the
+// given id into result.
+func describeForensicObject(apiClient client.Client, path string, id string, result interface{}) error {
+	return doRequest(apiClient, http.MethodGet, fmt.Sprintf("%s/%s", path, id), nil, result)
+}
+
+// updateForensicObject updates the forensic rule, profile or intent with the
+// given id.
+func updateForensicObject(apiClient client.Client, path string, id string, params interface{}) error {
+	return doRequest(apiClient, http.MethodPut, fmt.Sprintf("%s/%s", path, id), params, nil)
+}
+
+// deleteForensicObject deletes the forensic rule, profile or intent with the
+// given id.
+func deleteForensicObject(apiClient client.Client, path string, id string) error {
+	return doRequest(apiClient, http.MethodDelete, fmt.Sprintf("%s/%s", path, id), nil, nil)
+}
+
+// forensicRuleFromTerraform returns the forensic rule configured by the resource.
+func forensicRuleFromTerraform(d *schema.ResourceData) forensicRule {
+	return forensicRule{
+		RootAppScopeId: d.Get("root_app_scope_id").(string),
+		Name:           d.Get("name").(string),
+		Description:    d.Get("description").(string),
+		Expression:     d.Get("expression").(string),
+		Severity:       d.Get("severity").(string),
+		Actions:        stringsFromTerraform(d.Get("actions").([]interface{})),
+	}
+}
+
+func resourceTetrationForensicRuleCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(providerMeta).Client
+	var created forensicRule
+	err := createForensicObject(client, forensicRulesPath, forensicRuleFromTerraform(d), &created)
+	if err != nil {
+		return err
+	}
+	d.SetId(created.Id)
+	return resourceTetrationForensicRuleRead(d, meta)
+}
+
+func resourceTetrationForensicRuleUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(providerMeta).Client
+	err := updateForensicObject(client, forensicRulesPath, d.Id(), forensicRuleFromTerraform(d))
+	if err != nil {
+		return err
+	}
+	return resourceTetrationForensicRuleRead(d, meta)
+}
+
+func resourceTetrationForensicRuleRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(providerMeta).Client
+	var described forensicRule
+	err := describeForensicObject(client, forensicRulesPath, d.Id(), &described)
+	if err != nil {
+		return err
+	}
+	d.Set("root_app_scope_id", described.RootAppScopeId)
+	d.Set("name", described.Name)
+	d.Set("description", described.Description)
+	d.Set("expression", described.Expression)
+	d.Set("severity", described.Severity)
+	d.Set("actions", described.Actions)
+	return nil
+}
+
+func resourceTetrationForensicRuleDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(providerMeta).Client
+	return deleteForensicObject(client, forensicRulesPath, d.Id())
+}
+
+// forensicProfileFromTerraform returns the forensic profile configured by the resource.
+func forensicProfileFromTerraform(d *schema.ResourceData) forensicProfile {
+	return forensicProfile{
+		RootAppScopeId: d.Get("root_app_scope_id").(string),
+		Name:           d.Get("name").(string),
+		RuleIds:        stringsFromTerraform(d.Get("rule_ids").([]interface{})),
+	}
+}
+
+func resourceTetrationForensicProfileCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(providerMeta).Client
+	var created forensicProfile
+	err := createForensicObject(client, forensicProfilesPath, forensicProfileFromTerraform(d), &created)
+	if err != nil {
+		return err
+	}
+	d.SetId(created.Id)
+	return resourceTetrationForensicProfileRead(d, meta)
+}
+
+func resourceTetrationForensicProfileUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(providerMeta).Client
+	err := updateForensicObject(client, forensicProfilesPath, d.Id(), forensicProfileFromTerraform(d))
+	if err != nil {
+		return err
+	}
+	return resourceTetrationForensicProfileRead(d, meta)
+}
+
+func resourceTetrationForensicProfileRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(providerMeta).Client
+	var described forensicProfile
+	err := describeForensicObject(client, forensicProfilesPath, d.Id(), &described)
+	if err != nil {
+		return err
+	}
+	d.Set("root_app_scope_id", described.RootAppScopeId)
+	d.Set("name", described.Name)
+	d.Set("rule_ids", described.RuleIds)
+	return nil
+}
+
+func resourceTetrationForensicProfileDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(providerMeta).Client
+	return deleteForensicObject(client, forensicProfilesPath, d.Id())
+}
+
+func resourceTetrationForensicIntentCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(providerMeta).Client
+	var created forensicIntent
+	err := createForensicObject(client, forensicIntentsPath, forensicIntent{
+		FilterId:   d.Get("filter_id").(string),
+		ProfileIds: stringsFromTerraform(d.Get("profile_ids").([]interface{})),
+	}, &created)
+	if err != nil {
+		return err
+	}
+	d.SetId(created.Id)
+	return resourceTetrationForensicIntentRead(d, meta)
+}
+
+func resourceTetrationForensicIntentUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(providerMeta).Client
+	err := updateForensicObject(client, forensicIntentsPath, d.Id(), forensicIntent{
+		FilterId:   d.Get("filter_id").(string),
+		ProfileIds: stringsFromTerraform(d.Get("profile_ids").([]interface{})),
+	})
+	if err != nil {
+		return err
+	}
+	return resourceTetrationForensicIntentRead(d, meta)
+}
+
+func resourceTetrationForensicIntentRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(providerMeta).Client
+	var described forensicIntent
+	err := describeForensicObject(client, forensicIntentsPath, d.Id(), &described)
+	if err != nil {
+		return err
+	}
+	d.Set("filter_id", described.FilterId)
+	d.Set("profile_ids", described.ProfileIds)
+	return nil
+}
+
+func resourceTetrationForensicIntentDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(providerMeta).Client
+	return deleteForensicObject(client, forensicIntentsPath, d.Id())
+}
@@ -0,0 +1,253 @@
+package tetration
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+var (
+	// Comparison operators of forensic rule expressions, longest first.
+	ForensicComparisonOperators = []string{"<=", ">=", "!=", "=", "<", ">"}
+	// Comparison keywords of forensic rule expressions.
+	ForensicComparisonKeywords = []string{"CONTAINS", "MATCHES", "STARTSWITH", "ENDSWITH", "IN"}
+)
+
+// forensicToken is a lexical token of a forensic rule expression.
+type forensicToken struct {
+	// One of "ident", "string", "number", "op", "(", ")" or ",".
+	kind     string
+	value    string
+	position int
+}
+
+// tokenizeForensicExpression splits a forensic rule expression into tokens.
+func tokenizeForensicExpression(expression string) ([]forensicToken, error) {
+	var tokens []forensicToken
+	runes := []rune(expression)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(' || r == ')' || r == ',':
+			tokens = append(tokens, forensicToken{kind: string(r), value: string(r), position: i})
+			i++
+		case r == '"' || r == '\'':
+			start := i
+			i++
+			for i < len(runes) && runes[i] != r {
+				if runes[i] == '\\' {
+					i++
+				}
+				i++
+			}
+			if i >= len(runes) {
+				return nil, fmt.Errorf("Unterminated string starting at position %d", start+1)
+			}
+			i++
+			tokens = append(tokens, forensicToken{kind: "string", value: string(runes[start:i]), position: start})
+		case unicode.IsDigit(r) || (r == '-' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			start := i
+			i++
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, forensicToken{kind: "number", value: string(runes[start:i]), position: start})
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_' || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, forensicToken{kind: "ident", value: string(runes[start:i]), position: start})
+		default:
+			operator := ""
+			for _, candidate := range ForensicComparisonOperators {
+				if strings.HasPrefix(string(runes[i:]), candidate) {
+					operator = candidate
+					break
+				}
+			}
+			if operator == "" {
+				return nil, fmt.Errorf("Unexpected character %q at position %d", r, i+1)
+			}
+			tokens = append(tokens, forensicToken{kind: "op", value: operator, position: i})
+			i += len(operator)
+		}
+	}
+	return tokens, nil
+}
+
+// forensicExpressionParser is a recursive descent parser validating the syntax
+// of forensic rule expressions of the form
+//
+//	expression := term ("OR" term)*
+//	term       := factor ("AND" factor)*
+//	factor     := "NOT" factor | "(" expression ")" | field operator value
+//	value      := string | number | "true" | "false" | "(" value ("," value)* ")"
+type forensicExpressionParser struct {
+	tokens []forensicToken
+	next   int
+}
+
+// peek returns the next token, or an end token if all tokens were consumed.
+func (p *forensicExpressionParser) peek() forensicToken {
+	if p.next >= len(p.tokens) {
+		return forensicToken{kind: "end", value: "end of expression"}
+	}
+	return p.tokens[p.next]
+}
+
+// keyword returns whether the next token is the given case insensitive keyword.
+func (p *forensicExpressionParser) keyword(keyword string) bool {
+	token := p.peek()
+	return token.kind == "ident" && strings.EqualFold(token.value, keyword)
+}
+
+// unexpected returns an error describing the next token.
+func (p *forensicExpressionParser) unexpected(expected string) error {
+	token := p.peek()
+	if token.kind == "end" {
+		return fmt.Errorf("Expected %s but the expression ended", expected)
+	}
+	return fmt.Errorf("Expected %s at position %d, got %q", expected, token.position+1, token.value)
+}
+
+func (p *forensicExpressionParser) expression() error {
+	if err := p.term(); err != nil {
+		return err
+	}
+	for p.keyword("OR") {
+		p.next++
+		if err := p.term(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *forensicExpressionParser) term() error {
+	if err := p.factor(); err != nil {
+		return err
+	}
+	for p.keyword("AND") {
+		p.next++
+		if err := p.factor(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *forensicExpressionParser) factor() error {
+	if p.keyword("NOT") {
+		p.next++
+		return p.factor()
+	}
+	if p.peek().kind == "(" {
+		p.next++
+		if err := p.expression(); err != nil {
+			return err
+		}
+		if p.peek().kind != ")" {
+			return p.unexpected(`")"`)
+		}
+		p.next++
+		return nil
+	}
+	field := p.peek()
+	if field.kind != "ident" || isForensicKeyword(field.value) {
+		return p.unexpected("a field name")
+	}
+	p.next++
+	operator := p.peek()
+	isKeywordOperator := operator.kind == "ident" && isForensicComparisonKeyword(operator.value)
+	if operator.kind != "op" && !isKeywordOperator {
+		return p.unexpected(fmt.Sprintf("a comparison operator after %s", field.value))
+	}
+	p.next++
+	if isKeywordOperator && strings.EqualFold(operator.value, "IN") {
+		return p.valueList()
+	}
+	return p.value()
+}
+
+func (p *forensicExpressionParser) value() error {
+	token := p.peek()
+	switch {
+	case token.kind == "string" || token.kind == "number":
+	case token.kind == "ident" && (strings.EqualFold(token.value, "true") || strings.EqualFold(token.value, "false")):
+	default:
+		return p.unexpected("a string, number or boolean value")
+	}
+	p.next++
+	return nil
+}
+
+func (p *forensicExpressionParser) valueList() error {
+	if p.peek().kind != "(" {
+		return p.unexpected(`"(" starting a list of values`)
+	}
+	p.next++
+	for {
+		if err := p.value(); err != nil {
+			return err
+		}
+		if p.peek().kind != "," {
+			break
+		}
+		p.next++
+	}
+	if p.peek().kind != ")" {
+		return p.unexpected(`")"`)
+	}
+	p.next++
+	return nil
+}
+
+// isForensicComparisonKeyword returns whether a word is a comparison keyword.
+func isForensicComparisonKeyword(word string) bool {
+	for _, keyword := range ForensicComparisonKeywords {
+		if strings.EqualFold(word, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// isForensicKeyword returns whether a word is reserved by the expression syntax.
+func isForensicKeyword(word string) bool {
+	for _, keyword := range []string{"AND", "OR", "NOT"} {
+		if strings.EqualFold(word, keyword) {
+			return true
+		}
+	}
+	return isForensicComparisonKeyword(word)
+}
+
+// parseForensicExpression validates the syntax of a forensic rule expression.
+func parseForensicExpression(expression string) error {
+	tokens, err := tokenizeForensicExpression(expression)
+	if err != nil {
+		return err
+	}
+	if len(tokens) == 0 {
+		return fmt.Errorf("Expression is empty")
+	}
+	parser := forensicExpressionParser{tokens: tokens}
+	if err := parser.expression(); err != nil {
+		return err
+	}
+	if parser.peek().kind != "end" {
+		return parser.unexpected(`"AND", "OR" or the end of the expression`)
+	}
+	return nil
+}
+
+// validateForensicExpression validates the syntax of a forensic rule expression at plan time.
+func validateForensicExpression(v interface{}, k string) ([]string, []error) {
+	if err := parseForensicExpression(v.(string)); err != nil {
+		return nil, []error{fmt.Errorf("%s is not a valid forensic rule expression: %s", k, err)}
+	}
+	return nil, nil
+}
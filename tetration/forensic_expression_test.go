@@ -0,0 +1,37 @@
+package tetration
+
+import (
+	"testing"
+)
+
+func TestParseForensicExpression(t *testing.T) {
+	for _, expression := range []string{
+		`ProcessInfo.exePath = "/bin/sh"`,
+		`ProcessInfo.exePath = "/bin/sh" AND ProcessInfo.uid = 0`,
+		`(Process.exePath CONTAINS "nc" OR Process.exePath matches '.*ncat$') and not Process.uid != 0`,
+		`File.path STARTSWITH "/etc/" AND File.operation IN ("write", "delete")`,
+		`Network.bytes >= -1 AND Process.privileged = true`,
+	} {
+		if err := parseForensicExpression(expression); err != nil {
+			t.Errorf("Unexpected error for %q: %s", expression, err)
+		}
+	}
+	for _, expression := range []string{
+		"",
+		`ProcessInfo.exePath`,
+		`ProcessInfo.exePath = `,
+		`ProcessInfo.exePath = "/bin/sh`,
+		`ProcessInfo.exePath = "/bin/sh" AND`,
+		`(ProcessInfo.uid = 0`,
+		`ProcessInfo.uid = 0)`,
+		`ProcessInfo.uid == 0`,
+		`ProcessInfo.uid = 0 ProcessInfo.gid = 0`,
+		`AND = 0`,
+		`File.operation IN "write"`,
+		`ProcessInfo.uid = 0 & ProcessInfo.gid = 0`,
+	} {
+		if err := parseForensicExpression(expression); err == nil {
+			t.Errorf("Expected %q to be invalid", expression)
+		}
+	}
+}
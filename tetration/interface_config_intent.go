@@ -0,0 +1,147 @@
+package tetration
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+	client "github.com/tetration-exchange/terraform-go-sdk"
+)
+
+func resourceTetrationInterfaceConfigIntent() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceTetrationInterfaceConfigIntentCreate,
+		Update: resourceTetrationInterfaceConfigIntentUpdate,
+		Read:   resourceTetrationInterfaceConfigIntentRead,
+		Delete: resourceTetrationInterfaceConfigIntentDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		SchemaVersion: 1,
+
+		Schema: map[string]*schema.Schema{
+			"root_app_scope_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "ID of the root scope (tenant) whose interface config intents are managed.",
+			},
+			"mapping": {
+				Type:        schema.TypeList,
+				Required:    true,
+				MinItems:    1,
+				Description: "Mappings of the interfaces of agents to VRFs, highest priority first. Interfaces matching several filters are mapped to the VRF of the first.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"filter_id": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "ID of the inventory filter matching the interfaces, typically on their source network.",
+						},
+						"vrf_id": {
+							Type:         schema.TypeInt,
+							Required:     true,
+							ValidateFunc: validation.IntAtLeast(1),
+							Description:  "Numeric ID of the VRF the matching interfaces are mapped to.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// interfaceConfigIntent describes the mapping of the interfaces matching an inventory filter to a VRF.
+type interfaceConfigIntent struct {
+	FilterId string `json:"inventory_filter_id"`
+	VrfId    int    `json:"vrf_id"`
+}
+
+// interfaceConfigIntents describes the ordered interface config intents of a root scope.
+type interfaceConfigIntents struct {
+	RootAppScopeId string                  `json:"root_app_scope_id,omitempty"`
+	Intents        []interfaceConfigIntent `json:"intents"`
+}
+
+// describeInterfaceConfigIntents returns the interface config intents of a root
+// scope, highest priority first.
+func describeInterfaceConfigIntents(apiClient client.Client, rootAppScopeId string) (interfaceConfigIntents, error) {
+	var intents interfaceConfigIntents
+	err := doRequest(apiClient, http.MethodGet, "/inventory_config/interface_intents?root_app_scope_id="+url.QueryEscape(rootAppScopeId), nil, &intents)
+	return intents, err
+}
+
+// updateInterfaceConfigIntents replaces the interface config intents of a root
+// scope.
+func updateInterfaceConfigIntents(apiClient client.Client, params interfaceConfigIntents) error {
+	return doRequest(apiClient, http.MethodPost, "/inventory_config/interface_intents", params, nil)
+}
+
+// resourceTetrationInterfaceConfigIntentCreate takes ownership of the interface config
+// intents of the root scope, refusing to overwrite intents that exist outside of terraform.
+func resourceTetrationInterfaceConfigIntentCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(providerMeta).Client
+	rootAppScopeId := d.Get("root_app_scope_id").(string)
+	existing, err := describeInterfaceConfigIntents(client, rootAppScopeId)
+	if err != nil {
+		return err
+	}
+	if len(existing.Intents) > 0 {
+		return fmt.Errorf("Root scope %s already has %d interface config intents, import them with terraform import to manage them", rootAppScopeId, len(existing.Intents))
+	}
+	return resourceTetrationInterfaceConfigIntentUpdate(d, meta)
+}
+
+func resourceTetrationInterfaceConfigIntentUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(providerMeta).Client
+	rootAppScopeId := d.Get("root_app_scope_id").(string)
+	tfMappings := d.Get("mapping").([]interface{})
+	intents := make([]interfaceConfigIntent, 0, len(tfMappings))
+	for _, tfMapping := range tfMappings {
+		tf := tfMapping.(terraformObject)
+		intents = append(intents, interfaceConfigIntent{
+			FilterId: tf["filter_id"].(string),
+			VrfId:    tf["vrf_id"].(int),
+		})
+	}
+	err := updateInterfaceConfigIntents(client, interfaceConfigIntents{
+		RootAppScopeId: rootAppScopeId,
+		Intents:        intents,
+	})
+	if err != nil {
+		return err
+	}
+	d.SetId(rootAppScopeId)
+	return resourceTetrationInterfaceConfigIntentRead(d, meta)
+}
+
+func resourceTetrationInterfaceConfigIntentRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(providerMeta).Client
+	intents, err := describeInterfaceConfigIntents(client, d.Id())
+	if err != nil {
+		return err
+	}
+	tfMappings := make([]interface{}, 0, len(intents.Intents))
+	for _, intent := range intents.Intents {
+		tfMappings = append(tfMappings, terraformObject{
+			"filter_id": intent.FilterId,
+			"vrf_id":    intent.VrfId,
+		})
+	}
+	d.Set("root_app_scope_id", d.Id())
+	d.Set("mapping", tfMappings)
+	return nil
+}
+
+// resourceTetrationInterfaceConfigIntentDelete removes all interface config
+// intents of the root scope, mapping interfaces back to the VRF of the tenant.
+func resourceTetrationInterfaceConfigIntentDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(providerMeta).Client
+	return updateInterfaceConfigIntents(client, interfaceConfigIntents{
+		RootAppScopeId: d.Id(),
+		Intents:        []interfaceConfigIntent{},
+	})
+}
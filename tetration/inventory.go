@@ -0,0 +1,149 @@
+package tetration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	client "github.com/tetration-exchange/terraform-go-sdk"
+	tetration "github.com/tetration-exchange/terraform-go-sdk"
+)
+
+const (
+	// Maximum number of inventory items to request per page of search results.
+	InventorySearchPageSize = 1000
+	// Maximum number of inventory items retrieved when searching for all matching items.
+	MaxInventorySearchResults = 100000
+)
+
+// inventorySearchRequest wraps parameters for making a request to search
+// the inventory items matching a filter.
+type inventorySearchRequest struct {
+	// Inventory filter query, in the same form as the query of an inventory filter.
+	Filter json.RawMessage `json:"filter"`
+	// (Optional) Name of the scope to restrict the search to.
+	ScopeName string `json:"scopeName,omitempty"`
+	// (Optional) Dimensions (columns) to return for each item; defaults to all.
+	Dimensions []string `json:"dimensions,omitempty"`
+	Limit      int      `json:"limit"`
+	Offset     string   `json:"offset,omitempty"`
+}
+
+// inventorySearchResponse wraps a page of inventory search results.
+type inventorySearchResponse struct {
+	// Offset to request the next page of results with, empty for the last page.
+	Offset  string                   `json:"offset"`
+	Results []map[string]interface{} `json:"results"`
+}
+
+// searchInventory retrieves pages of the inventory items matching the search
+// until maxResults items were found, returning the items. If maxResults is 0
+// all items are retrieved, up to MaxInventorySearchResults.
+func searchInventory(apiClient client.Client, params inventorySearchRequest, maxResults int) ([]map[string]interface{}, error) {
+	var items []map[string]interface{}
+	params.Limit = InventorySearchPageSize
+	for {
+		if maxResults > 0 && maxResults-len(items) < params.Limit {
+			params.Limit = maxResults - len(items)
+		}
+		var page inventorySearchResponse
+		err := doRequest(apiClient, http.MethodPost, "/inventory/search", params, &page)
+		if err != nil {
+			return items, err
+		}
+		items = append(items, page.Results...)
+		if page.Offset == "" || len(page.Results) == 0 || (maxResults > 0 && len(items) >= maxResults) {
+			return items, nil
+		}
+		if maxResults == 0 && len(items) >= MaxInventorySearchResults {
+			return items, fmt.Errorf("Inventory search matched more than %d items, use a more specific query", MaxInventorySearchResults)
+		}
+		if page.Offset == params.Offset {
+			return items, fmt.Errorf("Inventory search returned the same offset %q twice", page.Offset)
+		}
+		params.Offset = page.Offset
+	}
+}
+
+// inventoryQuery is an inventory filter query. The SDK query types misspell
+// omitempty, so queries are converted to this type before being sent.
+type inventoryQuery struct {
+	Type    string           `json:"type"`
+	Field   string           `json:"field,omitempty"`
+	Value   interface{}      `json:"value,omitempty"`
+	Filters []inventoryQuery `json:"filters,omitempty"`
+}
+
+// inventoryQueryFromScopeQuery returns the query of a scope as an inventory query.
+func inventoryQueryFromScopeQuery(query tetration.ScopeQuery) inventoryQuery {
+	converted := inventoryQuery{
+		Type:  query.Type,
+		Field: query.Field,
+		Value: query.Value,
+	}
+	for _, filter := range query.Filters {
+		converted.Filters = append(converted.Filters, inventoryQueryFromScopeQuery(filter))
+	}
+	return converted
+}
+
+// inventoryAddressFilter returns an inventory filter query matching the
+// items with the given canonical IPv4/IPv6 address or within the given subnet.
+func inventoryAddressFilter(ip string) json.RawMessage {
+	filterType := "eq"
+	if strings.Contains(ip, "/") {
+		filterType = "subnet"
+	}
+	filter, _ := json.Marshal(terraformObject{
+		"type":  filterType,
+		"field": "ip",
+		"value": ip,
+	})
+	return filter
+}
+
+// inventoryItemToStrings returns the dimensions of an inventory item
+// as strings, omitting empty values.
+func inventoryItemToStrings(item map[string]interface{}) map[string]string {
+	values := make(map[string]string)
+	for dimension, value := range item {
+		switch typedValue := value.(type) {
+		case nil:
+			continue
+		case string:
+			if typedValue != "" {
+				values[dimension] = typedValue
+			}
+		case float64:
+			values[dimension] = strconv.FormatFloat(typedValue, 'f', -1, 64)
+		case bool:
+			values[dimension] = strconv.FormatBool(typedValue)
+		default:
+			encoded, err := json.Marshal(typedValue)
+			if err == nil {
+				values[dimension] = string(encoded)
+			}
+		}
+	}
+	return values
+}
+
+// mergeInventoryItems returns the dimensions shared by all the inventory
+// items, omitting the dimensions whose values differ between items.
+func mergeInventoryItems(items []map[string]interface{}) map[string]string {
+	if len(items) == 0 {
+		return map[string]string{}
+	}
+	merged := inventoryItemToStrings(items[0])
+	for _, item := range items[1:] {
+		values := inventoryItemToStrings(item)
+		for dimension, value := range merged {
+			if values[dimension] != value {
+				delete(merged, dimension)
+			}
+		}
+	}
+	return merged
+}
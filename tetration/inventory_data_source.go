@@ -0,0 +1,161 @@
+package tetration
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+var (
+	// Prefixes of inventory dimensions holding user annotations and orchestrator labels.
+	InventoryLabelPrefixes = []string{"user_", "orchestrator_"}
+)
+
+func dataSourceTetrationInventory() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceTetrationInventoryRead,
+
+		Schema: map[string]*schema.Schema{
+			"scope_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "ID of the scope to search the inventory of.",
+			},
+			"query": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.ValidateJsonString,
+				Description:  "JSON object representation of an inventory filter query, in the same form as the query of a tetration_filter.",
+			},
+			"dimensions": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "(Optional) Inventory dimensions to return for each workload, such as host_name or user_Environment; defaults to all dimensions.",
+			},
+			"max_results": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "(Optional) Maximum number of workloads to return, 0 for all matching workloads. Default value is 0.",
+			},
+			"workload_count": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Number of workloads returned.",
+			},
+			"workload": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Inventory items matching the query.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"ip": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "IP address of the workload.",
+						},
+						"hostname": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Hostname of the workload.",
+						},
+						"vrf_id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "ID of the VRF of the workload.",
+						},
+						"vrf_name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Name of the VRF of the workload.",
+						},
+						"os": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Operating system of the workload.",
+						},
+						"os_version": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Operating system version of the workload.",
+						},
+						"labels": {
+							Type:     schema.TypeMap,
+							Computed: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+							Description: "User annotations and orchestrator labels of the workload, keyed by dimension name (e.g. user_Environment).",
+						},
+						"attributes": {
+							Type:     schema.TypeMap,
+							Computed: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+							Description: "All returned dimensions of the workload.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// inventoryWorkloadToTerraform returns an inventory item as a terraform workload object.
+func inventoryWorkloadToTerraform(item map[string]interface{}) terraformObject {
+	attributes := inventoryItemToStrings(item)
+	labels := make(map[string]string)
+	for dimension, value := range attributes {
+		for _, prefix := range InventoryLabelPrefixes {
+			if strings.HasPrefix(dimension, prefix) {
+				labels[dimension] = value
+			}
+		}
+	}
+	return terraformObject{
+		"ip":         attributes["ip"],
+		"hostname":   attributes["host_name"],
+		"vrf_id":     attributes["vrf_id"],
+		"vrf_name":   attributes["vrf_name"],
+		"os":         attributes["os"],
+		"os_version": attributes["os_version"],
+		"labels":     labels,
+		"attributes": attributes,
+	}
+}
+
+func dataSourceTetrationInventoryRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(providerMeta).Client
+	scopeId := d.Get("scope_id").(string)
+	scope, err := client.DescribeScope(scopeId)
+	if err != nil {
+		return fmt.Errorf("Unable to describe scope %s: %s", scopeId, err)
+	}
+	query := d.Get("query").(string)
+	dimensions := stringsFromTerraform(d.Get("dimensions").([]interface{}))
+	items, err := searchInventory(client, inventorySearchRequest{
+		Filter:     json.RawMessage(query),
+		ScopeName:  scope.Name,
+		Dimensions: dimensions,
+	}, d.Get("max_results").(int))
+	if err != nil {
+		return err
+	}
+	workloads := make([]interface{}, 0, len(items))
+	for _, item := range items {
+		workloads = append(workloads, inventoryWorkloadToTerraform(item))
+	}
+	d.Set("workload", workloads)
+	d.Set("workload_count", len(workloads))
+	d.SetId(fmt.Sprintf("%s:%x", scopeId, sha256.Sum256([]byte(normalizeJSON(query)+strings.Join(dimensions, ",")))))
+	return nil
+}
@@ -0,0 +1,141 @@
+package tetration
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	tetration "github.com/tetration-exchange/terraform-go-sdk"
+)
+
+// testSearchClient returns a client for a server serving the given responses
+// at path, keyed by the offset in the request body (empty for requests without one).
+func testSearchClient(t *testing.T, path string, pages map[string]interface{}) (tetration.Client, func()) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var params struct {
+			Offset string `json:"offset"`
+		}
+		json.NewDecoder(r.Body).Decode(&params)
+		page, ok := pages[params.Offset]
+		if !ok || !strings.HasSuffix(r.URL.Path, path) {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(page)
+	}))
+	apiClient, err := tetration.New(tetration.Config{
+		APIKey:    "key",
+		APISecret: strings.Repeat("s", 40),
+		APIURL:    server.URL,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	return apiClient, server.Close
+}
+
+func TestSearchInventory(t *testing.T) {
+	apiClient, closeServer := testSearchClient(t, "/inventory/search", map[string]interface{}{
+		"":  inventorySearchResponse{Offset: "2", Results: []map[string]interface{}{{"ip": "10.0.0.1"}, {"ip": "10.0.0.2"}}},
+		"2": inventorySearchResponse{Results: []map[string]interface{}{{"ip": "10.0.0.3"}}},
+	})
+	defer closeServer()
+	items, err := searchInventory(apiClient, inventorySearchRequest{}, 0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if len(items) != 3 || items[2]["ip"] != "10.0.0.3" {
+		t.Errorf("Unexpected items %v", items)
+	}
+	items, err = searchInventory(apiClient, inventorySearchRequest{}, 2)
+	if err != nil || len(items) != 2 {
+		t.Errorf("Expected 2 items, got %v (%v)", items, err)
+	}
+}
+
+func TestSearchInventoryStopsOnRepeatedOffset(t *testing.T) {
+	apiClient, closeServer := testSearchClient(t, "/inventory/search", map[string]interface{}{
+		"":  inventorySearchResponse{Offset: "2", Results: []map[string]interface{}{{"ip": "10.0.0.1"}}},
+		"2": inventorySearchResponse{Offset: "2", Results: []map[string]interface{}{{"ip": "10.0.0.2"}}},
+	})
+	defer closeServer()
+	if _, err := searchInventory(apiClient, inventorySearchRequest{}, 0); err == nil {
+		t.Errorf("Expected an error for a repeated offset")
+	}
+}
+
+func TestInventoryItemToStrings(t *testing.T) {
+	var item map[string]interface{}
+	err := json.Unmarshal([]byte(`{
+		"ip": "10.0.0.1",
+		"host_name": "",
+		"bytes": 1234567,
+		"large": 123456789012345,
+		"ratio": 0.25,
+		"negative": -3,
+		"enabled": true,
+		"tags": ["a", "b"],
+		"missing": null
+	}`), &item)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	expected := map[string]string{
+		"ip":       "10.0.0.1",
+		"bytes":    "1234567",
+		"large":    "123456789012345",
+		"ratio":    "0.25",
+		"negative": "-3",
+		"enabled":  "true",
+		"tags":     `["a","b"]`,
+	}
+	values := inventoryItemToStrings(item)
+	if len(values) != len(expected) {
+		t.Errorf("Expected %v, got %v", expected, values)
+	}
+	for dimension, value := range expected {
+		if values[dimension] != value {
+			t.Errorf("Expected %s to be %q, got %q", dimension, value, values[dimension])
+		}
+	}
+}
+
+func TestFlowToTerraform(t *testing.T) {
+	totals := make(map[string]float64)
+	for _, flow := range []map[string]interface{}{
+		{"src_address": "10.0.0.1", "fwd_bytes": float64(2500000), "fwd_pkts": float64(10)},
+		{"src_address": "10.0.0.2", "fwd_bytes": float64(1500000), "fwd_pkts": float64(20)},
+	} {
+		tf := flowToTerraform(flow, []string{"fwd_bytes", "fwd_pkts"}, totals)
+		metrics := tf["metrics"].(map[string]string)
+		if metrics["fwd_bytes"] != "2500000" && metrics["fwd_bytes"] != "1500000" {
+			t.Errorf("Unexpected fwd_bytes metric %q", metrics["fwd_bytes"])
+		}
+		if _, ok := tf["dimensions"].(map[string]string)["fwd_bytes"]; ok {
+			t.Errorf("Expected fwd_bytes to only be a metric")
+		}
+	}
+	if totals["fwd_bytes"] != 4000000 || totals["fwd_pkts"] != 30 {
+		t.Errorf("Unexpected totals %v", totals)
+	}
+}
+
+func TestInventoryQueryFromScopeQuery(t *testing.T) {
+	query := tetration.ScopeQuery{
+		Type: "and",
+		Filters: []tetration.ScopeQuery{
+			{Type: "subnet", Field: "ip", Value: "10.0.0.0/8"},
+			{Type: "eq", Field: "user_enabled", Value: false},
+		},
+	}
+	encoded, err := json.Marshal(inventoryQueryFromScopeQuery(query))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	expected := `{"type":"and","filters":[{"type":"subnet","field":"ip","value":"10.0.0.0/8"},{"type":"eq","field":"user_enabled","value":false}]}`
+	if string(encoded) != expected {
+		t.Errorf("Expected %s, got %s", expected, encoded)
+	}
+}
@@ -0,0 +1,182 @@
+package tetration
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	yaml "github.com/zclconf/go-cty-yaml"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+// kubeconfig describes the parts of a kubectl config file needed to
+// connect to the API server of a Kubernetes cluster.
+type kubeconfig struct {
+	CurrentContext string `json:"current-context"`
+	Clusters       []struct {
+		Name    string `json:"name"`
+		Cluster struct {
+			Server                   string `json:"server"`
+			CertificateAuthorityData string `json:"certificate-authority-data"`
+			CertificateAuthority     string `json:"certificate-authority"`
+			InsecureSkipTlsVerify    bool   `json:"insecure-skip-tls-verify"`
+		} `json:"cluster"`
+	} `json:"clusters"`
+	Contexts []struct {
+		Name    string `json:"name"`
+		Context struct {
+			Cluster string `json:"cluster"`
+			User    string `json:"user"`
+		} `json:"context"`
+	} `json:"contexts"`
+	Users []struct {
+		Name string `json:"name"`
+		User struct {
+			Token                 string `json:"token"`
+			TokenFile             string `json:"tokenFile"`
+			ClientCertificateData string `json:"client-certificate-data"`
+			ClientCertificate     string `json:"client-certificate"`
+			ClientKeyData         string `json:"client-key-data"`
+			ClientKey             string `json:"client-key"`
+		} `json:"user"`
+	} `json:"users"`
+}
+
+// kubeconfigCredentials wraps the API server and credentials of a kubeconfig context.
+type kubeconfigCredentials struct {
+	HostName          string
+	Port              int
+	CaCertificate     string
+	Insecure          bool
+	Token             string
+	ClientCertificate string
+	ClientKey         string
+}
+
+// hash returns a hash of the credentials, used to detect rotated tokens and certificates.
+func (credentials kubeconfigCredentials) hash() string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(fmt.Sprintf("%#v", credentials))))
+}
+
+// readKubeconfig returns the kubeconfig contents, reading them from path if
+// contents is empty.
+func readKubeconfig(contents string, path string) ([]byte, error) {
+	if contents != "" {
+		return []byte(contents), nil
+	}
+	document, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to read kubeconfig %s: %s", path, err)
+	}
+	return document, nil
+}
+
+// parseKubeconfig extracts the API server, CA bundle and credentials of the
+// named context (or the current context if empty) from a kubeconfig document.
+// Relative file paths in the kubeconfig are resolved against directory, the
+// directory of the kubeconfig file, as kubectl does.
+func parseKubeconfig(document []byte, contextName string, directory string) (kubeconfigCredentials, error) {
+	var credentials kubeconfigCredentials
+	value, err := yaml.Standard.Unmarshal(document, cty.DynamicPseudoType)
+	if err != nil {
+		return credentials, fmt.Errorf("Unable to parse kubeconfig: %s", err)
+	}
+	encoded, err := ctyjson.Marshal(value, value.Type())
+	if err != nil {
+		return credentials, fmt.Errorf("Unable to parse kubeconfig: %s", err)
+	}
+	var config kubeconfig
+	if err := json.Unmarshal(encoded, &config); err != nil {
+		return credentials, fmt.Errorf("Unable to parse kubeconfig: %s", err)
+	}
+	if contextName == "" {
+		contextName = config.CurrentContext
+	}
+	clusterName, userName := "", ""
+	for _, context := range config.Contexts {
+		if context.Name == contextName {
+			clusterName, userName = context.Context.Cluster, context.Context.User
+		}
+	}
+	if clusterName == "" {
+		return credentials, fmt.Errorf("No context named %q exists in kubeconfig", contextName)
+	}
+	found := false
+	for _, cluster := range config.Clusters {
+		if cluster.Name != clusterName {
+			continue
+		}
+		found = true
+		server, err := url.Parse(cluster.Cluster.Server)
+		if err != nil || server.Hostname() == "" {
+			return credentials, fmt.Errorf("Invalid server %q of cluster %s in kubeconfig", cluster.Cluster.Server, clusterName)
+		}
+		credentials.HostName = server.Hostname()
+		credentials.Port = 443
+		if _, port, err := net.SplitHostPort(server.Host); err == nil {
+			credentials.Port, _ = strconv.Atoi(port)
+		}
+		credentials.Insecure = cluster.Cluster.InsecureSkipTlsVerify
+		credentials.CaCertificate, err = kubeconfigData(cluster.Cluster.CertificateAuthorityData, cluster.Cluster.CertificateAuthority, directory)
+		if err != nil {
+			return credentials, err
+		}
+	}
+	if !found {
+		return credentials, fmt.Errorf("No cluster named %q exists in kubeconfig", clusterName)
+	}
+	for _, user := range config.Users {
+		if user.Name != userName {
+			continue
+		}
+		credentials.Token = user.User.Token
+		if credentials.Token == "" && user.User.TokenFile != "" {
+			credentials.Token, err = kubeconfigData("", user.User.TokenFile, directory)
+			if err != nil {
+				return credentials, err
+			}
+			credentials.Token = strings.TrimSpace(credentials.Token)
+		}
+		credentials.ClientCertificate, err = kubeconfigData(user.User.ClientCertificateData, user.User.ClientCertificate, directory)
+		if err != nil {
+			return credentials, err
+		}
+		credentials.ClientKey, err = kubeconfigData(user.User.ClientKeyData, user.User.ClientKey, directory)
+		if err != nil {
+			return credentials, err
+		}
+		return credentials, nil
+	}
+	return credentials, fmt.Errorf("No user named %q exists in kubeconfig", userName)
+}
+
+// kubeconfigData returns base64 encoded inline data or the contents of the file
+// at path, relative to directory unless absolute.
+func kubeconfigData(data string, path string, directory string) (string, error) {
+	if data != "" {
+		decoded, err := base64.StdEncoding.DecodeString(data)
+		if err != nil {
+			return "", fmt.Errorf("Invalid base64 data in kubeconfig: %s", err)
+		}
+		return string(decoded), nil
+	}
+	if path == "" {
+		return "", nil
+	}
+	if !filepath.IsAbs(path) && directory != "" {
+		path = filepath.Join(directory, path)
+	}
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("Unable to read %s referenced by kubeconfig: %s", path, err)
+	}
+	return string(contents), nil
+}
@@ -0,0 +1,128 @@
+package tetration
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testKubeconfig = `
+apiVersion: v1
+kind: Config
+current-context: production
+clusters:
+- name: production-cluster
+  cluster:
+    server: https://k8s.example.com:6443
+    certificate-authority-data: Q0EgQlVORExF
+- name: staging-cluster
+  cluster:
+    server: https://staging.example.com
+    insecure-skip-tls-verify: true
+contexts:
+- name: production
+  context:
+    cluster: production-cluster
+    user: tetration
+- name: staging
+  context:
+    cluster: staging-cluster
+    user: staging-user
+users:
+- name: tetration
+  user:
+    token: production-token
+- name: staging-user
+  user:
+    client-certificate-data: Q0VSVA==
+    client-key-data: S0VZ
+`
+
+func TestParseKubeconfigCurrentContext(t *testing.T) {
+	credentials, err := parseKubeconfig([]byte(testKubeconfig), "", "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	expected := kubeconfigCredentials{
+		HostName:      "k8s.example.com",
+		Port:          6443,
+		CaCertificate: "CA BUNDLE",
+		Token:         "production-token",
+	}
+	if credentials != expected {
+		t.Errorf("Expected %#v, got %#v", expected, credentials)
+	}
+}
+
+func TestParseKubeconfigNamedContext(t *testing.T) {
+	credentials, err := parseKubeconfig([]byte(testKubeconfig), "staging", "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	expected := kubeconfigCredentials{
+		HostName:          "staging.example.com",
+		Port:              443,
+		Insecure:          true,
+		ClientCertificate: "CERT",
+		ClientKey:         "KEY",
+	}
+	if credentials != expected {
+		t.Errorf("Expected %#v, got %#v", expected, credentials)
+	}
+	if _, err := parseKubeconfig([]byte(testKubeconfig), "missing", ""); err == nil {
+		t.Errorf("Expected an error for a missing context")
+	}
+}
+
+func TestKubeconfigCredentialsFromTerraformRelativePaths(t *testing.T) {
+	directory, err := ioutil.TempDir("", "kubeconfig")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	defer os.RemoveAll(directory)
+	files := map[string]string{
+		"config": `
+current-context: production
+clusters:
+- name: production-cluster
+  cluster:
+    server: https://k8s.example.com:6443
+    certificate-authority: certs/ca.crt
+contexts:
+- name: production
+  context:
+    cluster: production-cluster
+    user: tetration
+users:
+- name: tetration
+  user:
+    tokenFile: token
+    client-certificate: certs/client.crt
+    client-key: certs/client.key
+`,
+		"certs/ca.crt":     "CA BUNDLE",
+		"certs/client.crt": "CLIENT CERTIFICATE",
+		"certs/client.key": "CLIENT KEY",
+		"token":            "file-token\n",
+	}
+	for name, contents := range files {
+		path := filepath.Join(directory, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if err := ioutil.WriteFile(path, []byte(contents), 0600); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	}
+	credentials, ok, err := kubeconfigCredentialsFromTerraform(terraformObject{
+		"kubeconfig_path": filepath.Join(directory, "config"),
+	})
+	if err != nil || !ok {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if credentials.CaCertificate != "CA BUNDLE" || credentials.Token != "file-token" ||
+		credentials.ClientCertificate != "CLIENT CERTIFICATE" || credentials.ClientKey != "CLIENT KEY" {
+		t.Errorf("Expected paths relative to the kubeconfig to be read, got %#v", credentials)
+	}
+}
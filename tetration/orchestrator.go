@@ -0,0 +1,566 @@
+package tetration
+
+import (
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+	client "github.com/tetration-exchange/terraform-go-sdk"
+)
+
+const (
+	KubernetesOrchestratorType = "kubernetes"
+	VcenterOrchestratorType    = "vcenter"
+	AwsOrchestratorType        = "aws"
+	DnsOrchestratorType        = "dns"
+	InfobloxOrchestratorType   = "infoblox"
+	F5OrchestratorType         = "f5"
+	NetscalerOrchestratorType  = "netscaler"
+	// Prefix of the inventory dimensions of kubernetes labels, unless reported otherwise.
+	DefaultKubernetesLabelPrefix = "orchestrator_"
+)
+
+var (
+	ValidOrchestratorTypes = []string{KubernetesOrchestratorType, VcenterOrchestratorType, AwsOrchestratorType, DnsOrchestratorType, InfobloxOrchestratorType, F5OrchestratorType, NetscalerOrchestratorType}
+)
+
+func resourceTetrationOrchestrator() *schema.Resource {
+	return &schema.Resource{
+		Create:        resourceTetrationOrchestratorCreate,
+		Update:        resourceTetrationOrchestratorUpdate,
+		Read:          resourceTetrationOrchestratorRead,
+		Delete:        resourceTetrationOrchestratorDelete,
+		CustomizeDiff: resourceTetrationOrchestratorCustomizeDiff,
+
+		SchemaVersion: 1,
+
+		Schema: map[string]*schema.Schema{
+			"root_app_scope_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "ID of the root scope the orchestrator belongs to.",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "User-specified name for the orchestrator.",
+			},
+			"description": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "(Optional) User-specified description of the orchestrator.",
+			},
+			"host": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Computed:    true,
+				Description: "(Optional) Host of the orchestrator API. Required for all types except aws, and taken from the server of the kubeconfig for kubernetes orchestrators configured with a kubeconfig.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"host_name": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "Hostname or IP address of the host.",
+						},
+						"port": {
+							Type:         schema.TypeInt,
+							Required:     true,
+							ValidateFunc: validation.IntBetween(1, 65535),
+							Description:  "Port of the host.",
+						},
+					},
+				},
+			},
+			"ca_certificate": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "(Optional) PEM encoded CA certificates to verify the TLS certificates of the hosts with. Taken from the kubeconfig for kubernetes orchestrators configured with a kubeconfig.",
+			},
+			"insecure": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Computed:    true,
+				Description: "(Optional) Skip the verification of the TLS certificates of the hosts. Defaults to false, or to insecure-skip-tls-verify of the kubeconfig.",
+			},
+			"use_secure_connector": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "(Optional) Connect to the hosts through the secure connector tunnel of the root scope. Default value is false.",
+			},
+			KubernetesOrchestratorType: {
+				Type:          schema.TypeList,
+				Optional:      true,
+				MaxItems:      1,
+				ConflictsWith: orchestratorTypesExcept(KubernetesOrchestratorType),
+				Description:   "(Optional) Kubernetes cluster to import pods, services and their labels from.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"auth_token": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Sensitive:   true,
+							Description: "(Optional) Bearer token of a service account to authenticate with. Write-only, never read back.",
+						},
+						"client_certificate": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "(Optional) PEM encoded client certificate to authenticate with.",
+						},
+						"client_key": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Sensitive:   true,
+							Description: "(Optional) PEM encoded key of the client certificate. Write-only, never read back.",
+						},
+						"kubeconfig": {
+							Type:          schema.TypeString,
+							Optional:      true,
+							Sensitive:     true,
+							ConflictsWith: []string{"kubernetes.0.kubeconfig_path"},
+							Description:   "(Optional) Contents of a kubeconfig to take the API server, CA bundle, token and client certificate from. Write-only, never read back.",
+						},
+						"kubeconfig_path": {
+							Type:          schema.TypeString,
+							Optional:      true,
+							ConflictsWith: []string{"kubernetes.0.kubeconfig"},
+							Description:   "(Optional) Path of a kubeconfig to take the API server, CA bundle, token and client certificate from. Rotated credentials in the file are detected through kubeconfig_hash and updated in place.",
+						},
+						"context": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "(Optional) Name of the kubeconfig context to use; defaults to the current context of the kubeconfig.",
+						},
+					},
+				},
+			},
+			VcenterOrchestratorType:   orchestratorUsernamePasswordSchema(VcenterOrchestratorType, "VMware vCenter to import virtual machines and their attributes from."),
+			InfobloxOrchestratorType:  orchestratorUsernamePasswordSchema(InfobloxOrchestratorType, "Infoblox IPAM to import networks, hosts and their extensible attributes from."),
+			F5OrchestratorType:        orchestratorUsernamePasswordSchema(F5OrchestratorType, "F5 BIG-IP load balancer to import virtual servers from and enforce policies on."),
+			NetscalerOrchestratorType: orchestratorUsernamePasswordSchema(NetscalerOrchestratorType, "Citrix NetScaler load balancer to import virtual servers from and enforce policies on."),
+			AwsOrchestratorType: {
+				Type:          schema.TypeList,
+				Optional:      true,
+				MaxItems:      1,
+				ConflictsWith: orchestratorTypesExcept(AwsOrchestratorType),
+				Description:   "(Optional) AWS account to import EC2 instances and their tags from.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"access_key_id": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "ID of the access key to authenticate with.",
+						},
+						"secret_access_key": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Sensitive:   true,
+							Description: "Secret of the access key. Write-only, never read back.",
+						},
+						"region": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "AWS region to import instances from, e.g. us-west-1.",
+						},
+					},
+				},
+			},
+			DnsOrchestratorType: {
+				Type:          schema.TypeList,
+				Optional:      true,
+				MaxItems:      1,
+				ConflictsWith: orchestratorTypesExcept(DnsOrchestratorType),
+				Description:   "(Optional) DNS server to import A/AAAA and CNAME records from through zone transfers.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"zones": {
+							Type:     schema.TypeList,
+							Required: true,
+							MinItems: 1,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+							Description: "DNS zones to import records from.",
+						},
+					},
+				},
+			},
+			"type": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: fmt.Sprintf("Type of the orchestrator, one of [%s].", strings.Join(ValidOrchestratorTypes, ", ")),
+			},
+			"kubeconfig_hash": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Hash of the API server and credentials taken from the kubeconfig, used to detect rotated credentials.",
+			},
+			"pod_label_prefix": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Prefix of the inventory dimensions holding the labels of the pods of kubernetes orchestrators.",
+			},
+			"service_label_prefix": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Prefix of the inventory dimensions holding the labels of the services of kubernetes orchestrators.",
+			},
+			"connection_status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Status of the connection of Tetration to the orchestrator, as of the last refresh.",
+			},
+			"connection_error": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Error of the connection of Tetration to the orchestrator, empty if connected.",
+			},
+		},
+	}
+}
+
+// orchestratorTypesExcept returns the names of the blocks of all other orchestrator types.
+func orchestratorTypesExcept(orchestratorType string) []string {
+	var types []string
+	for _, validType := range ValidOrchestratorTypes {
+		if validType != orchestratorType {
+			types = append(types, validType)
+		}
+	}
+	return types
+}
+
+// orchestratorUsernamePasswordSchema returns the schema of the block of an
+// orchestrator type authenticating with a username and password.
+func orchestratorUsernamePasswordSchema(orchestratorType string, description string) *schema.Schema {
+	return &schema.Schema{
+		Type:          schema.TypeList,
+		Optional:      true,
+		MaxItems:      1,
+		ConflictsWith: orchestratorTypesExcept(orchestratorType),
+		Description:   "(Optional) " + description,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"username": {
+					Type:        schema.TypeString,
+					Required:    true,
+					Description: "Username to authenticate with.",
+				},
+				"password": {
+					Type:        schema.TypeString,
+					Required:    true,
+					Sensitive:   true,
+					Description: "Password to authenticate with. Write-only, never read back.",
+				},
+			},
+		},
+	}
+}
+
+// orchestratorHost describes a host of the API of an orchestrator.
+type orchestratorHost struct {
+	HostName string `json:"host_name"`
+	Port     int    `json:"port"`
+}
+
+// orchestrator describes an external orchestrator Tetration imports inventory
+// metadata from. Credentials are write-only and omitted from responses.
+type orchestrator struct {
+	Id                    string             `json:"id,omitempty"`
+	Name                  string             `json:"name"`
+	Description           string             `json:"description"`
+	Type                  string             `json:"type"`
+	HostsList             []orchestratorHost `json:"hosts_list"`
+	CaCertificate         string             `json:"ca_certificate"`
+	Insecure              bool               `json:"insecure"`
+	SecureConnectorTunnel bool               `json:"secure_connector_tunnel"`
+	Username              string             `json:"username,omitempty"`
+	Password              string             `json:"password,omitempty"`
+	AuthToken             string             `json:"auth_token,omitempty"`
+	Certificate           string             `json:"certificate,omitempty"`
+	Key                   string             `json:"key,omitempty"`
+	AwsAccessKeyId        string             `json:"aws_access_key_id,omitempty"`
+	AwsSecretAccessKey    string             `json:"aws_secret_access_key,omitempty"`
+	AwsRegion             string             `json:"aws_region,omitempty"`
+	DnsZones              []string           `json:"dns_zones,omitempty"`
+	// Prefixes of the inventory dimensions of the labels of kubernetes pods and services.
+	PodLabelPrefix     string `json:"pod_label_prefix,omitempty"`
+	ServiceLabelPrefix string `json:"service_label_prefix,omitempty"`
+	// Status of the connection to the orchestrator, e.g. “Success” or “Failure”.
+	ConnectionStatus string `json:"connection_status,omitempty"`
+	// Reason of the last connection failure.
+	FailureReason string `json:"failure_reason,omitempty"`
+}
+
+// createOrchestrator creates an orchestrator in the root scope, returning the
+// created orchestrator.
+func createOrchestrator(apiClient client.Client, rootAppScopeId string, params orchestrator) (orchestrator, error) {
+	var created orchestrator
+	err := doRequest(apiClient, http.MethodPost, fmt.Sprintf("/orchestrator/%s", rootAppScopeId), params, &created)
+	return created, err
+}
+
+// describeOrchestrator returns the orchestrator with the given id in the root
+// scope.
+func describeOrchestrator(apiClient client.Client, rootAppScopeId string, orchestratorId string) (orchestrator, error) {
+	var described orchestrator
+	err := doRequest(apiClient, http.MethodGet, fmt.Sprintf("/orchestrator/%s/%s", rootAppScopeId, orchestratorId), nil, &described)
+	return described, err
+}
+
+// updateOrchestrator updates an orchestrator in the root scope, leaving omitted
+// credentials unchanged.
+func updateOrchestrator(apiClient client.Client, rootAppScopeId string, orchestratorId string, params orchestrator) error {
+	return doRequest(apiClient, http.MethodPut, fmt.Sprintf("/orchestrator/%s/%s", rootAppScopeId, orchestratorId), params, nil)
+}
+
+func deleteOrchestrator(apiClient client.Client, rootAppScopeId string, orchestratorId string) error {
+	return doRequest(apiClient, http.MethodDelete, fmt.Sprintf("/orchestrator/%s/%s", rootAppScopeId, orchestratorId), nil, nil)
+}
+
+// orchestratorTypeFromTerraform returns the type of the single orchestrator
+// type block that is set, failing if none or several are set.
+func orchestratorTypeFromTerraform(get func(string) interface{}) (string, error) {
+	var types []string
+	for _, orchestratorType := range ValidOrchestratorTypes {
+		if len(get(orchestratorType).([]interface{})) > 0 {
+			types = append(types, orchestratorType)
+		}
+	}
+	if len(types) != 1 {
+		return "", fmt.Errorf("Exactly one of the [%s] blocks must be specified", strings.Join(ValidOrchestratorTypes, ", "))
+	}
+	return types[0], nil
+}
+
+// orchestratorFromTerraform returns the orchestrator configured by the resource, including its credentials.
+func orchestratorFromTerraform(d *schema.ResourceData) (orchestrator, error) {
+	orchestratorType, err := orchestratorTypeFromTerraform(d.Get)
+	if err != nil {
+		return orchestrator{}, err
+	}
+	params := orchestrator{
+		Name:                  d.Get("name").(string),
+		Description:           d.Get("description").(string),
+		Type:                  orchestratorType,
+		HostsList:             []orchestratorHost{},
+		CaCertificate:         d.Get("ca_certificate").(string),
+		Insecure:              d.Get("insecure").(bool),
+		SecureConnectorTunnel: d.Get("use_secure_connector").(bool),
+	}
+	for _, tfHost := range d.Get("host").([]interface{}) {
+		tf := tfHost.(terraformObject)
+		params.HostsList = append(params.HostsList, orchestratorHost{
+			HostName: tf["host_name"].(string),
+			Port:     tf["port"].(int),
+		})
+	}
+	tf, ok := d.Get(orchestratorType).([]interface{})[0].(terraformObject)
+	if !ok {
+		return params, fmt.Errorf("The %s block must not be empty", orchestratorType)
+	}
+	switch orchestratorType {
+	case KubernetesOrchestratorType:
+		params.AuthToken = tf["auth_token"].(string)
+		params.Certificate = tf["client_certificate"].(string)
+		params.Key = tf["client_key"].(string)
+		credentials, ok, err := kubeconfigCredentialsFromTerraform(tf)
+		if err != nil {
+			return params, err
+		}
+		if ok {
+			// Explicit credentials take precedence over the kubeconfig
+			if params.AuthToken == "" {
+				params.AuthToken = credentials.Token
+			}
+			if params.Certificate == "" {
+				params.Certificate = credentials.ClientCertificate
+				params.Key = credentials.ClientKey
+			}
+			params.HostsList = []orchestratorHost{{HostName: credentials.HostName, Port: credentials.Port}}
+			params.CaCertificate = credentials.CaCertificate
+			params.Insecure = params.Insecure || credentials.Insecure
+		}
+	case AwsOrchestratorType:
+		params.AwsAccessKeyId = tf["access_key_id"].(string)
+		params.AwsSecretAccessKey = tf["secret_access_key"].(string)
+		params.AwsRegion = tf["region"].(string)
+	case DnsOrchestratorType:
+		params.DnsZones = stringsFromTerraform(tf["zones"].([]interface{}))
+	default:
+		params.Username = tf["username"].(string)
+		params.Password = tf["password"].(string)
+	}
+	if orchestratorType != AwsOrchestratorType && len(params.HostsList) == 0 {
+		return params, fmt.Errorf("At least one host is required for %s orchestrators", orchestratorType)
+	}
+	return params, nil
+}
+
+// kubeconfigCredentialsFromTerraform returns the credentials of the kubeconfig
+// of a kubernetes block, whether a kubeconfig is configured.
+func kubeconfigCredentialsFromTerraform(tf terraformObject) (kubeconfigCredentials, bool, error) {
+	contents, _ := tf["kubeconfig"].(string)
+	path, _ := tf["kubeconfig_path"].(string)
+	if contents == "" && path == "" {
+		return kubeconfigCredentials{}, false, nil
+	}
+	document, err := readKubeconfig(contents, path)
+	if err != nil {
+		return kubeconfigCredentials{}, true, err
+	}
+	contextName, _ := tf["context"].(string)
+	directory := ""
+	if contents == "" {
+		directory = filepath.Dir(path)
+	}
+	credentials, err := parseKubeconfig(document, contextName, directory)
+	return credentials, true, err
+}
+
+// kubeconfigHashFromTerraform returns the hash of the kubeconfig credentials of
+// the kubernetes block, empty if no kubeconfig is configured.
+func kubeconfigHashFromTerraform(tfKubernetes []interface{}) (string, error) {
+	if len(tfKubernetes) == 0 || tfKubernetes[0] == nil {
+		return "", nil
+	}
+	credentials, ok, err := kubeconfigCredentialsFromTerraform(tfKubernetes[0].(terraformObject))
+	if err != nil || !ok {
+		return "", err
+	}
+	return credentials.hash(), nil
+}
+
+// orchestratorBlockToTerraform returns the block of the type of an orchestrator,
+// keeping the write-only credentials of the previous block.
+func orchestratorBlockToTerraform(described orchestrator, previous []interface{}) []interface{} {
+	tf := terraformObject{}
+	if len(previous) > 0 && previous[0] != nil {
+		for key, value := range previous[0].(terraformObject) {
+			tf[key] = value
+		}
+	}
+	switch described.Type {
+	case KubernetesOrchestratorType:
+		tf["client_certificate"] = described.Certificate
+	case AwsOrchestratorType:
+		tf["access_key_id"] = described.AwsAccessKeyId
+		tf["region"] = described.AwsRegion
+	case DnsOrchestratorType:
+		tf["zones"] = described.DnsZones
+	default:
+		tf["username"] = described.Username
+	}
+	return []interface{}{tf}
+}
+
+func resourceTetrationOrchestratorCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(providerMeta).Client
+	params, err := orchestratorFromTerraform(d)
+	if err != nil {
+		return err
+	}
+	created, err := createOrchestrator(client, d.Get("root_app_scope_id").(string), params)
+	if err != nil {
+		return err
+	}
+	d.SetId(created.Id)
+	kubeconfigHash, _ := kubeconfigHashFromTerraform(d.Get(KubernetesOrchestratorType).([]interface{}))
+	d.Set("kubeconfig_hash", kubeconfigHash)
+	return resourceTetrationOrchestratorRead(d, meta)
+}
+
+func resourceTetrationOrchestratorUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(providerMeta).Client
+	params, err := orchestratorFromTerraform(d)
+	if err != nil {
+		return err
+	}
+	err = updateOrchestrator(client, d.Get("root_app_scope_id").(string), d.Id(), params)
+	if err != nil {
+		return err
+	}
+	kubeconfigHash, _ := kubeconfigHashFromTerraform(d.Get(KubernetesOrchestratorType).([]interface{}))
+	d.Set("kubeconfig_hash", kubeconfigHash)
+	return resourceTetrationOrchestratorRead(d, meta)
+}
+
+func resourceTetrationOrchestratorRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(providerMeta).Client
+	described, err := describeOrchestrator(client, d.Get("root_app_scope_id").(string), d.Id())
+	if err != nil {
+		return err
+	}
+	tfHosts := make([]interface{}, 0, len(described.HostsList))
+	for _, host := range described.HostsList {
+		tfHosts = append(tfHosts, terraformObject{
+			"host_name": host.HostName,
+			"port":      host.Port,
+		})
+	}
+	d.Set("name", described.Name)
+	d.Set("description", described.Description)
+	d.Set("type", described.Type)
+	d.Set("host", tfHosts)
+	d.Set("ca_certificate", described.CaCertificate)
+	d.Set("insecure", described.Insecure)
+	d.Set("use_secure_connector", described.SecureConnectorTunnel)
+	d.Set(described.Type, orchestratorBlockToTerraform(described, d.Get(described.Type).([]interface{})))
+	if described.Type == KubernetesOrchestratorType {
+		if described.PodLabelPrefix == "" {
+			described.PodLabelPrefix = DefaultKubernetesLabelPrefix
+		}
+		if described.ServiceLabelPrefix == "" {
+			described.ServiceLabelPrefix = DefaultKubernetesLabelPrefix
+		}
+	}
+	d.Set("pod_label_prefix", described.PodLabelPrefix)
+	d.Set("service_label_prefix", described.ServiceLabelPrefix)
+	d.Set("connection_status", described.ConnectionStatus)
+	d.Set("connection_error", described.FailureReason)
+	return nil
+}
+
+func resourceTetrationOrchestratorDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(providerMeta).Client
+	return deleteOrchestrator(client, d.Get("root_app_scope_id").(string), d.Id())
+}
+
+// resourceTetrationOrchestratorCustomizeDiff validates that exactly one
+// orchestrator type block is set, replaces the orchestrator when its type changes
+// and plans an in place update when the credentials of the kubeconfig changed.
+func resourceTetrationOrchestratorCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	for _, orchestratorType := range ValidOrchestratorTypes {
+		if !d.NewValueKnown(orchestratorType) {
+			return nil
+		}
+	}
+	orchestratorType, err := orchestratorTypeFromTerraform(d.Get)
+	if err != nil {
+		return err
+	}
+	kubeconfigHash, err := kubeconfigHashFromTerraform(d.Get(KubernetesOrchestratorType).([]interface{}))
+	if err != nil {
+		return err
+	}
+	if d.Get("kubeconfig_hash").(string) != kubeconfigHash {
+		if err := d.SetNew("kubeconfig_hash", kubeconfigHash); err != nil {
+			return err
+		}
+	}
+	if d.Id() == "" {
+		return d.SetNew("type", orchestratorType)
+	}
+	if d.Get("type").(string) != orchestratorType {
+		if err := d.SetNew("type", orchestratorType); err != nil {
+			return err
+		}
+		return d.ForceNew("type")
+	}
+	return nil
+}
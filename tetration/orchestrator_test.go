@@ -0,0 +1,34 @@
+package tetration
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestOrchestratorFromTerraformEmptyBlock(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceTetrationOrchestrator().Schema, map[string]interface{}{
+		"name":       "cluster",
+		"kubernetes": []interface{}{map[string]interface{}{}},
+	})
+	if _, err := orchestratorFromTerraform(d); err == nil {
+		t.Errorf("Expected an error for an empty kubernetes block")
+	}
+}
+
+func TestOrchestratorFromTerraform(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceTetrationOrchestrator().Schema, map[string]interface{}{
+		"name": "cluster",
+		"host": []interface{}{map[string]interface{}{"host_name": "k8s.example.com", "port": 6443}},
+		"kubernetes": []interface{}{map[string]interface{}{
+			"auth_token": "token",
+		}},
+	})
+	params, err := orchestratorFromTerraform(d)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if params.Type != KubernetesOrchestratorType || params.AuthToken != "token" || len(params.HostsList) != 1 || params.HostsList[0].Port != 6443 {
+		t.Errorf("Unexpected orchestrator %+v", params)
+	}
+}
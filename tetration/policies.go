@@ -0,0 +1,189 @@
+package tetration
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+
+	client "github.com/tetration-exchange/terraform-go-sdk"
+)
+
+const (
+	AbsolutePolicyRank = "ABSOLUTE"
+	DefaultPolicyRank  = "DEFAULT"
+)
+
+// applicationPolicyRanks maps the policy blocks of an application
+// to the rank the policies in that block are created with.
+var applicationPolicyRanks = []struct {
+	attribute string
+	rank      string
+}{
+	{attribute: "absolute_policy", rank: AbsolutePolicyRank},
+	{attribute: "default_policy", rank: DefaultPolicyRank},
+}
+
+// applicationPolicy describes a policy as returned by the Tetration API.
+type applicationPolicy struct {
+	// Unique identifier for the policy.
+	Id string `json:"id"`
+	// ID of a cluster, user inventory filter, or application scope.
+	ConsumerFilterId string `json:"consumer_filter_id"`
+	// ID of a cluster, user inventory filter, or application scope.
+	ProviderFilterId string `json:"provider_filter_id"`
+	// “ALLOW” or “DENY”
+	Action string `json:"action"`
+	// Used to sort policies within a rank, lower values take precedence.
+	Priority int `json:"priority"`
+	// “ABSOLUTE”, “DEFAULT” or “CATCHALL”
+	Rank string `json:"rank"`
+}
+
+// applicationPolicies wraps the policies of a version of an application.
+type applicationPolicies struct {
+	AbsolutePolicies []applicationPolicy `json:"absolute_policies"`
+	DefaultPolicies  []applicationPolicy `json:"default_policies"`
+	// “ALLOW” or “DENY”
+	CatchAllAction string `json:"catch_all_action"`
+}
+
+// byRank returns the policies grouped by their rank.
+func (policies applicationPolicies) byRank() map[string][]applicationPolicy {
+	return map[string][]applicationPolicy{
+		AbsolutePolicyRank: policies.AbsolutePolicies,
+		DefaultPolicyRank:  policies.DefaultPolicies,
+	}
+}
+
+// byId returns the policies indexed by their id, with the rank
+// of each policy set to the rank it was listed under.
+func (policies applicationPolicies) byId() map[string]applicationPolicy {
+	policiesById := make(map[string]applicationPolicy)
+	for rank, rankPolicies := range policies.byRank() {
+		for _, policy := range rankPolicies {
+			policy.Rank = rank
+			policiesById[policy.Id] = policy
+		}
+	}
+	return policiesById
+}
+
+// updatePolicyRequest wraps parameters for making a request to update a policy.
+type updatePolicyRequest struct {
+	// Used to sort policies within a rank, lower values take precedence.
+	Priority int `json:"priority,omitempty"`
+	// “ABSOLUTE” or “DEFAULT”
+	Rank string `json:"rank,omitempty"`
+	// ID of a cluster, user inventory filter, or application scope.
+	ProviderFilterId string `json:"provider_filter_id,omitempty"`
+}
+
+// updateCatchAllRequest wraps parameters for making a request to update
+// the catch all action of an application.
+type updateCatchAllRequest struct {
+	// “ALLOW” or “DENY”
+	Action string `json:"action"`
+}
+
+// describeApplicationPolicies describes the policies of the latest version of
+// an application, returning the policies.
+func describeApplicationPolicies(apiClient client.Client, applicationId string) (applicationPolicies, error) {
+	var policies applicationPolicies
+	err := doRequest(apiClient, http.MethodGet, fmt.Sprintf("/applications/%s/policies", applicationId), nil, &policies)
+	return policies, err
+}
+
+// updatePolicy updates the priority and/or rank of a policy, returning the
+// updated policy.
+func updatePolicy(apiClient client.Client, policyId string, params updatePolicyRequest) (applicationPolicy, error) {
+	var policy applicationPolicy
+	err := doRequest(apiClient, http.MethodPut, fmt.Sprintf("/policies/%s", policyId), params, &policy)
+	return policy, err
+}
+
+func updateCatchAllAction(apiClient client.Client, applicationId string, action string) error {
+	return doRequest(apiClient, http.MethodPut, fmt.Sprintf("/policies/%s/catch_all", applicationId), updateCatchAllRequest{Action: action}, nil)
+}
+
+// policiesByPriority returns the policies ordered by their effective priority.
+func policiesByPriority(policies []applicationPolicy) []applicationPolicy {
+	ordered := make([]applicationPolicy, len(policies))
+	copy(ordered, policies)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].Priority < ordered[j].Priority
+	})
+	return ordered
+}
+
+// policyKey returns a key identifying a terraform policy block by its content,
+// ignoring its id, priority and the rank it is declared with, so that
+// blocks can be matched across reorders.
+func policyKey(tf terraformObject) string {
+	key := fmt.Sprintf("%s|%s|%s|%s|%s|%s|%s|%s|%s",
+		tf["consumer_filter_id"], tf["consumer_filter_name"], tf["consumer_scope_name"],
+		tf["provider_filter_id"], tf["provider_filter_name"], tf["provider_scope_name"],
+		tf["provider_application_id"], tf["provider_service_name"],
+		tf["action"])
+	if value, ok := tf["layer_4_network_policy"].([]interface{}); ok {
+		for _, tfLayer4NetworkPolicy := range value {
+			if tfLayer4NetworkPolicy == nil {
+				continue
+			}
+			l4 := tfLayer4NetworkPolicy.(terraformObject)
+			key += fmt.Sprintf("|%v:%v:%v", l4["protocol"], l4["port_range"], l4["approved"])
+		}
+	}
+	return key
+}
+
+// policyKeys returns the content keys of all policy blocks of an application
+// regardless of their rank, counting duplicate policies.
+func policyKeys(tfPoliciesByRank ...interface{}) map[string]int {
+	keys := make(map[string]int)
+	for _, tfPolicies := range tfPoliciesByRank {
+		for _, tfPolicy := range tfPolicies.([]interface{}) {
+			if tfPolicy == nil {
+				continue
+			}
+			keys[policyKey(tfPolicy.(terraformObject))]++
+		}
+	}
+	return keys
+}
+
+// samePolicyKeys reports whether two sets of policy keys contain the same policies.
+func samePolicyKeys(a map[string]int, b map[string]int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for key, count := range a {
+		if b[key] != count {
+			return false
+		}
+	}
+	return true
+}
+
+// assignPolicyIds sets the id of policy blocks that do not yet have one
+// (e.g. after creating the application) by matching them in order against
+// the not yet claimed policies of the same rank, ordered by priority.
+func assignPolicyIds(tfPolicies []interface{}, rankPolicies []applicationPolicy, claimed map[string]bool) {
+	unclaimed := []applicationPolicy{}
+	for _, policy := range policiesByPriority(rankPolicies) {
+		if !claimed[policy.Id] {
+			unclaimed = append(unclaimed, policy)
+		}
+	}
+	for _, tfPolicy := range tfPolicies {
+		if tfPolicy == nil || len(unclaimed) == 0 {
+			continue
+		}
+		tf := tfPolicy.(terraformObject)
+		if tf["id"] != nil && tf["id"].(string) != "" {
+			continue
+		}
+		tf["id"] = unclaimed[0].Id
+		claimed[unclaimed[0].Id] = true
+		unclaimed = unclaimed[1:]
+	}
+}
@@ -0,0 +1,60 @@
+package tetration
+
+import (
+	"testing"
+)
+
+func testPolicyBlock(consumerFilterId string, port int) terraformObject {
+	return terraformObject{
+		"id":                   "",
+		"priority":             0,
+		"consumer_filter_id":   consumerFilterId,
+		"consumer_filter_name": "",
+		"consumer_scope_name":  "",
+		"provider_filter_id":   "provider",
+		"provider_filter_name": "",
+		"provider_scope_name":  "",
+		"action":               "ALLOW",
+		"layer_4_network_policy": []interface{}{
+			terraformObject{"protocol": 6, "port_range": []interface{}{port, port}, "approved": false},
+		},
+	}
+}
+
+func TestPolicyKeysIgnoreOrderAndRank(t *testing.T) {
+	a := testPolicyBlock("a", 80)
+	b := testPolicyBlock("b", 443)
+	reordered := testPolicyBlock("b", 443)
+	reordered["id"] = "5ed68d36497d4f06fc5c5869"
+	reordered["priority"] = 10
+	before := policyKeys([]interface{}{a, b}, []interface{}{})
+	after := policyKeys([]interface{}{}, []interface{}{reordered, a})
+	if !samePolicyKeys(before, after) {
+		t.Errorf("Expected reordered policies %v to match %v", after, before)
+	}
+}
+
+func TestPolicyKeysDetectChangedPolicies(t *testing.T) {
+	before := policyKeys([]interface{}{testPolicyBlock("a", 80), testPolicyBlock("a", 80)}, []interface{}{})
+	after := policyKeys([]interface{}{testPolicyBlock("a", 80), testPolicyBlock("a", 8080)}, []interface{}{})
+	if samePolicyKeys(before, after) {
+		t.Errorf("Expected changed policies %v not to match %v", after, before)
+	}
+}
+
+func TestAssignPolicyIdsMatchesUnclaimedPoliciesByPriority(t *testing.T) {
+	tfPolicies := []interface{}{testPolicyBlock("a", 80), testPolicyBlock("b", 443)}
+	tfPolicies[1].(terraformObject)["id"] = "second"
+	rankPolicies := []applicationPolicy{
+		{Id: "third", Priority: 3},
+		{Id: "second", Priority: 2},
+		{Id: "first", Priority: 1},
+	}
+	assignPolicyIds(tfPolicies, rankPolicies, map[string]bool{"second": true})
+	if id := tfPolicies[0].(terraformObject)["id"]; id != "first" {
+		t.Errorf("Expected policy id first, got %s", id)
+	}
+	if id := tfPolicies[1].(terraformObject)["id"]; id != "second" {
+		t.Errorf("Expected policy id second, got %s", id)
+	}
+}
@@ -0,0 +1,372 @@
+package tetration
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+	client "github.com/tetration-exchange/terraform-go-sdk"
+	tetration "github.com/tetration-exchange/terraform-go-sdk"
+)
+
+const (
+	PermittedFlowCategory = "PERMITTED"
+	RejectedFlowCategory  = "REJECTED"
+	EscapedFlowCategory   = "ESCAPED"
+	// Maximum number of flows to request per page of live analysis results.
+	LiveAnalysisPageSize = 1000
+	// Default maximum number of flows to retrieve for a live analysis.
+	DefaultLiveAnalysisMaxResults = 100000
+)
+
+func dataSourceTetrationPolicyAnalysis() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceTetrationPolicyAnalysisRead,
+
+		Schema: map[string]*schema.Schema{
+			"application_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "ID of the application to analyze.",
+			},
+			"version": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "(Optional) Version of the application to analyze in the form “v10” or “p10”; defaults to the latest version.",
+			},
+			"start_time": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.ValidateRFC3339TimeString,
+				Description:  "(Optional) RFC 3339 timestamp of the start of the time window to run live analysis for. Required unless flow is specified.",
+			},
+			"end_time": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.ValidateRFC3339TimeString,
+				Description:  "(Optional) RFC 3339 timestamp of the end of the time window to run live analysis for. Required unless flow is specified.",
+			},
+			"top_escaped_limit": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      10,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "(Optional) Maximum number of escaped conversations to return. Default value is 10.",
+			},
+			"max_results": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      DefaultLiveAnalysisMaxResults,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  fmt.Sprintf("(Optional) Maximum number of observed flows to retrieve for live analysis. Default value is %d.", DefaultLiveAnalysisMaxResults),
+			},
+			"flow": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: "(Optional) Single flow to run quick analysis for instead of live analysis. Only flow_decision is set for quick analysis.",
+				Elem: &schema.Resource{
+					Schema: quickAnalysisFlowSchema(),
+				},
+			},
+			"flow_decision": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "“ALLOW” or “DENY” decision of the quick analysis of the flow. Only set when flow is specified.",
+			},
+			"permitted_count": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Number of observed flows permitted by the policies. Only set for live analysis.",
+			},
+			"rejected_count": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Number of observed flows rejected by the policies. Only set for live analysis.",
+			},
+			"escaped_count": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Number of observed flows that were not rejected but should have been according to the policies. Only set for live analysis.",
+			},
+			"truncated": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether live analysis stopped at max_results, so the counts only cover part of the observed flows.",
+			},
+			"escaped_conversation": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Escaped conversations ordered by their number of flows, most frequent first. Only set for live analysis.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"consumer_address": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "IP address of the consumer.",
+						},
+						"provider_address": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "IP address of the provider.",
+						},
+						"provider_port": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "Port of the provider.",
+						},
+						"protocol": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Protocol of the conversation.",
+						},
+						"flow_count": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "Number of escaped flows of the conversation.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// quickAnalysisRequest wraps parameters for making a request to analyze
+// a single flow against the policies of a root scope.
+type quickAnalysisRequest struct {
+	ConsumerIp   string `json:"consumer_ip"`
+	ProviderIp   string `json:"provider_ip"`
+	Protocol     int    `json:"protocol"`
+	ProviderPort int    `json:"provider_port,omitempty"`
+	// (Optional) Analyze the given application instead of the enforced policies.
+	ApplicationId string `json:"app_id,omitempty"`
+	// (Optional) Version of the application to analyze; defaults to latest.
+	Version string `json:"app_version,omitempty"`
+}
+
+// quickAnalysisFlowSchema returns the schema of the attributes describing
+// the flow of a quick analysis.
+func quickAnalysisFlowSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"consumer_ip": {
+			Type:         schema.TypeString,
+			Required:     true,
+			ValidateFunc: validation.SingleIP(),
+			Description:  "IP address of the consumer (client) of the flow.",
+		},
+		"provider_ip": {
+			Type:         schema.TypeString,
+			Required:     true,
+			ValidateFunc: validation.SingleIP(),
+			Description:  "IP address of the provider (server) of the flow.",
+		},
+		"protocol": {
+			Type:        schema.TypeInt,
+			Required:    true,
+			Description: "Protocol integer value of the flow; for example, 6 for TCP.",
+		},
+		"port": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			ValidateFunc: validation.IntBetween(0, 65535),
+			Description:  "(Optional) Provider port of the flow.",
+		},
+	}
+}
+
+// quickAnalysisRequestFromTerraform returns a request to analyze the flow
+// described by the attributes of quickAnalysisFlowSchema.
+func quickAnalysisRequestFromTerraform(tf terraformObject, applicationId string, version string) quickAnalysisRequest {
+	return quickAnalysisRequest{
+		ConsumerIp:    tf["consumer_ip"].(string),
+		ProviderIp:    tf["provider_ip"].(string),
+		Protocol:      tf["protocol"].(int),
+		ProviderPort:  tf["port"].(int),
+		ApplicationId: applicationId,
+		Version:       version,
+	}
+}
+
+// flowId identifies the analyzed flow, for use in data source ids.
+func (params quickAnalysisRequest) flowId() string {
+	return fmt.Sprintf("%s:%s:%d:%d", params.ConsumerIp, params.ProviderIp, params.Protocol, params.ProviderPort)
+}
+
+// quickAnalysisPolicy describes the policy a flow matched during quick analysis.
+type quickAnalysisPolicy struct {
+	Id            string `json:"policy_id"`
+	ApplicationId string `json:"app_id"`
+	Action        string `json:"action"`
+	Rank          string `json:"rank"`
+	Priority      int    `json:"priority"`
+}
+
+// quickAnalysisResponse wraps the result of a quick analysis of a flow.
+type quickAnalysisResponse struct {
+	// “ALLOW” or “DENY”
+	PolicyDecision string `json:"policy_decision"`
+	// Policy of the consumer scope that matched the outbound flow.
+	OutboundPolicy *quickAnalysisPolicy `json:"outbound_policy"`
+	// Policy of the provider scope that matched the inbound flow.
+	InboundPolicy *quickAnalysisPolicy `json:"inbound_policy"`
+}
+
+// liveAnalysisRequest wraps parameters for making a request to retrieve
+// the observed flows of an application categorized by its policies.
+type liveAnalysisRequest struct {
+	StartTime string `json:"t0"`
+	EndTime   string `json:"t1"`
+	Version   string `json:"version,omitempty"`
+	Limit     int    `json:"limit"`
+	Offset    string `json:"offset,omitempty"`
+}
+
+// liveAnalysisFlow describes an observed flow and its policy analysis category.
+type liveAnalysisFlow struct {
+	ConsumerAddress string `json:"src_address"`
+	ProviderAddress string `json:"dst_address"`
+	ProviderPort    int    `json:"dst_port"`
+	Protocol        string `json:"proto"`
+	// “PERMITTED”, “REJECTED”, “ESCAPED” or “MISDROPPED”
+	Category string `json:"category"`
+}
+
+// liveAnalysisResponse wraps a page of live analysis results.
+type liveAnalysisResponse struct {
+	// Offset to request the next page of results with, empty for the last page.
+	Offset  string             `json:"offset"`
+	Results []liveAnalysisFlow `json:"results"`
+}
+
+// quickAnalysis analyzes a single flow against the policies of the root scope,
+// returning the analysis.
+func quickAnalysis(apiClient client.Client, rootAppScopeId string, params quickAnalysisRequest) (quickAnalysisResponse, error) {
+	var analysis quickAnalysisResponse
+	err := doRequest(apiClient, http.MethodPost, fmt.Sprintf("/policies/%s/quick_analysis", rootAppScopeId), params, &analysis)
+	return analysis, err
+}
+
+// liveAnalysis retrieves the pages of the live analysis of an application for
+// the requested time window, up to maxResults flows. It also reports whether
+// results were left out.
+func liveAnalysis(apiClient client.Client, applicationId string, params liveAnalysisRequest, maxResults int) ([]liveAnalysisFlow, bool, error) {
+	var flows []liveAnalysisFlow
+	for {
+		var page liveAnalysisResponse
+		err := doRequest(apiClient, http.MethodPost, fmt.Sprintf("/live_analysis/%s", applicationId), params, &page)
+		if err != nil {
+			return flows, false, err
+		}
+		flows = append(flows, page.Results...)
+		if len(flows) >= maxResults {
+			return flows[:maxResults], len(flows) > maxResults || page.Offset != "", nil
+		}
+		// Stop when the server doesn't advance the offset, instead of requesting the same page forever
+		if page.Offset == "" || page.Offset == params.Offset || len(page.Results) == 0 {
+			return flows, false, nil
+		}
+		params.Offset = page.Offset
+	}
+}
+
+// rootAppScopeIdForApplication returns the id of the root scope an application
+// belongs to.
+func rootAppScopeIdForApplication(apiClient client.Client, applicationId string) (string, error) {
+	application, err := apiClient.DescribeApplication(tetration.DescribeApplicationRequest{
+		ApplicationId: applicationId,
+	})
+	if err != nil {
+		return "", err
+	}
+	scope, err := apiClient.DescribeScope(application.AppScopeId)
+	if err != nil {
+		return "", err
+	}
+	if scope.RootAppScopeId == "" {
+		return scope.Id, nil
+	}
+	return scope.RootAppScopeId, nil
+}
+
+func dataSourceTetrationPolicyAnalysisRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(providerMeta).Client
+	applicationId := d.Get("application_id").(string)
+	version := d.Get("version").(string)
+	if value, ok := d.GetOk("flow"); ok {
+		tf := value.([]interface{})[0].(terraformObject)
+		rootAppScopeId, err := rootAppScopeIdForApplication(client, applicationId)
+		if err != nil {
+			return err
+		}
+		params := quickAnalysisRequestFromTerraform(tf, applicationId, version)
+		analysis, err := quickAnalysis(client, rootAppScopeId, params)
+		if err != nil {
+			return err
+		}
+		d.Set("flow_decision", analysis.PolicyDecision)
+		d.SetId(fmt.Sprintf("%s:%s:%s", applicationId, version, params.flowId()))
+		return nil
+	}
+	startTime := d.Get("start_time").(string)
+	endTime := d.Get("end_time").(string)
+	if startTime == "" || endTime == "" {
+		return errors.New("start_time and end_time are required to run live analysis when no flow is specified")
+	}
+	flows, truncated, err := liveAnalysis(client, applicationId, liveAnalysisRequest{
+		StartTime: startTime,
+		EndTime:   endTime,
+		Version:   version,
+		Limit:     LiveAnalysisPageSize,
+	}, d.Get("max_results").(int))
+	if err != nil {
+		return err
+	}
+	counts := make(map[string]int)
+	escapedConversations := make(map[liveAnalysisFlow]int)
+	for _, flow := range flows {
+		counts[flow.Category]++
+		if flow.Category == EscapedFlowCategory {
+			escapedConversations[flow]++
+		}
+	}
+	d.Set("permitted_count", counts[PermittedFlowCategory])
+	d.Set("rejected_count", counts[RejectedFlowCategory])
+	d.Set("escaped_count", counts[EscapedFlowCategory])
+	d.Set("truncated", truncated)
+	d.Set("escaped_conversation", topEscapedConversations(escapedConversations, d.Get("top_escaped_limit").(int)))
+	d.SetId(fmt.Sprintf("%s:%s:%s:%s", applicationId, version, startTime, endTime))
+	return nil
+}
+
+// topEscapedConversations returns up to limit escaped conversations
+// as terraform objects, ordered by their number of flows.
+func topEscapedConversations(conversations map[liveAnalysisFlow]int, limit int) []interface{} {
+	ordered := make([]liveAnalysisFlow, 0, len(conversations))
+	for conversation := range conversations {
+		ordered = append(ordered, conversation)
+	}
+	sort.Slice(ordered, func(i, j int) bool {
+		if conversations[ordered[i]] != conversations[ordered[j]] {
+			return conversations[ordered[i]] > conversations[ordered[j]]
+		}
+		return fmt.Sprint(ordered[i]) < fmt.Sprint(ordered[j])
+	})
+	if len(ordered) > limit {
+		ordered = ordered[:limit]
+	}
+	tfConversations := make([]interface{}, 0, len(ordered))
+	for _, conversation := range ordered {
+		tfConversations = append(tfConversations, terraformObject{
+			"consumer_address": conversation.ConsumerAddress,
+			"provider_address": conversation.ProviderAddress,
+			"provider_port":    conversation.ProviderPort,
+			"protocol":         conversation.Protocol,
+			"flow_count":       conversations[conversation],
+		})
+	}
+	return tfConversations
+}
@@ -32,6 +32,12 @@ func Provider() terraform.ResourceProvider {
 				DefaultFunc: schema.EnvDefaultFunc("TETRATION_API_URL", nil),
 				Description: "URL for a Tetration API.",
 			},
+			"default_tenant": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("TETRATION_DEFAULT_TENANT", ""),
+				Description: "Name of the root scope (tenant) used by resources that do not specify a tenant.",
+			},
 			"disable_tls_verification": {
 				Type:        schema.TypeBool,
 				Optional:    true,
@@ -40,17 +46,54 @@ func Provider() terraform.ResourceProvider {
 			},
 		},
 		ResourcesMap: map[string]*schema.Resource{
-			"tetration_filter":      resourceTetrationFilter(),
-			"tetration_scope":       resourceTetrationScope(),
-			"tetration_tag":         resourceTetrationTag(),
-			"tetration_user":        resourceTetrationUser(),
-			"tetration_application": resourceTetrationApplication(),
-			"tetration_role":        resourceTetrationRole(),
+			"tetration_filter":                    resourceTetrationFilter(),
+			"tetration_scope":                     resourceTetrationScope(),
+			"tetration_tag":                       resourceTetrationTag(),
+			"tetration_user":                      resourceTetrationUser(),
+			"tetration_application":               resourceTetrationApplication(),
+			"tetration_role":                      resourceTetrationRole(),
+			"tetration_annotations":               resourceTetrationAnnotations(),
+			"tetration_annotation_schema":         resourceTetrationAnnotationSchema(),
+			"tetration_agent_config_profile":      resourceTetrationAgentConfigProfile(),
+			"tetration_agent_config_intent":       resourceTetrationAgentConfigIntent(),
+			"tetration_agent_config_intent_order": resourceTetrationAgentConfigIntentOrder(),
+			"tetration_agent_upgrade":             resourceTetrationAgentUpgrade(),
+			"tetration_agent_cleanup":             resourceTetrationAgentCleanup(),
+			"tetration_orchestrator":              resourceTetrationOrchestrator(),
+			"tetration_virtual_appliance":         resourceTetrationVirtualAppliance(),
+			"tetration_connector":                 resourceTetrationConnector(),
+			"tetration_secure_connector_token":    resourceTetrationSecureConnectorToken(),
+			"tetration_vrf":                       resourceTetrationVrf(),
+			"tetration_collection_rule":           resourceTetrationCollectionRule(),
+			"tetration_interface_config_intent":   resourceTetrationInterfaceConfigIntent(),
+			"tetration_datatap":                   resourceTetrationDatatap(),
+			"tetration_alert_config":              resourceTetrationAlertConfig(),
+			"tetration_forensic_rule":             resourceTetrationForensicRule(),
+			"tetration_forensic_profile":          resourceTetrationForensicProfile(),
+			"tetration_forensic_intent":           resourceTetrationForensicIntent(),
+			"tetration_api_key":                   resourceTetrationApiKey(),
+		},
+		DataSourcesMap: map[string]*schema.Resource{
+			"tetration_policy_analysis": dataSourceTetrationPolicyAnalysis(),
+			"tetration_quick_analysis":  dataSourceTetrationQuickAnalysis(),
+			"tetration_tag":             dataSourceTetrationTag(),
+			"tetration_agents":          dataSourceTetrationAgents(),
+			"tetration_flows":           dataSourceTetrationFlows(),
+			"tetration_inventory":       dataSourceTetrationInventory(),
+			"tetration_vrf":             dataSourceTetrationVrf(),
 		},
 		ConfigureFunc: configureClient,
 	}
 }
 
+// providerMeta wraps the configured Tetration client together with
+// provider level settings for use by resources and data sources.
+type providerMeta struct {
+	Client client.Client
+	// Name of the root scope (tenant) used by resources that do not specify a tenant.
+	DefaultTenant string
+}
+
 func configureClient(d *schema.ResourceData) (interface{}, error) {
 	config := client.Config{
 		APIKey:                 d.Get("api_key").(string),
@@ -65,7 +108,10 @@ func configureClient(d *schema.ResourceData) (interface{}, error) {
 	if err != nil {
 		return nil, err
 	}
-	return client, nil
+	return providerMeta{
+		Client:        client,
+		DefaultTenant: d.Get("default_tenant").(string),
+	}, nil
 }
 
 // validate validates the config needed to initialize a tetration client,
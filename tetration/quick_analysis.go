@@ -0,0 +1,111 @@
+package tetration
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourceTetrationQuickAnalysis() *schema.Resource {
+	dataSourceSchema := map[string]*schema.Schema{
+		"root_app_scope_id": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Computed:    true,
+			Description: "(Optional) ID of the root scope whose enforced policies the flow is analyzed against. Required unless application_id is specified.",
+		},
+		"application_id": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "(Optional) ID of an application to analyze the flow against instead of the enforced policies.",
+		},
+		"version": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "(Optional) Version of the application to analyze in the form “v10” or “p10”; defaults to the latest version.",
+		},
+		"action": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "“ALLOW” or “DENY” decision for the flow.",
+		},
+		"policy_id": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "ID of the policy that decided the flow, empty if the flow matched the catch all action.",
+		},
+		"policy_application_id": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "ID of the application of the policy that decided the flow.",
+		},
+		"policy_rank": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "“ABSOLUTE”, “DEFAULT” or “CATCHALL” rank of the policy that decided the flow.",
+		},
+		"policy_priority": {
+			Type:        schema.TypeInt,
+			Computed:    true,
+			Description: "Priority of the policy that decided the flow.",
+		},
+	}
+	for key, flowSchema := range quickAnalysisFlowSchema() {
+		dataSourceSchema[key] = flowSchema
+	}
+	return &schema.Resource{
+		Read: dataSourceTetrationQuickAnalysisRead,
+
+		Schema: dataSourceSchema,
+	}
+}
+
+// decidingPolicy returns the policy responsible for the decision of a
+// quick analysis: the first of the outbound and inbound policies whose
+// action matches the decision, or nil if neither was matched.
+func (analysis quickAnalysisResponse) decidingPolicy() *quickAnalysisPolicy {
+	for _, policy := range []*quickAnalysisPolicy{analysis.OutboundPolicy, analysis.InboundPolicy} {
+		if policy != nil && policy.Action == analysis.PolicyDecision {
+			return policy
+		}
+	}
+	return nil
+}
+
+func dataSourceTetrationQuickAnalysisRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(providerMeta).Client
+	applicationId := d.Get("application_id").(string)
+	rootAppScopeId := d.Get("root_app_scope_id").(string)
+	if rootAppScopeId == "" {
+		if applicationId == "" {
+			return errors.New("One of root_app_scope_id or application_id must be specified")
+		}
+		var err error
+		rootAppScopeId, err = rootAppScopeIdForApplication(client, applicationId)
+		if err != nil {
+			return err
+		}
+	}
+	tf := terraformObject{}
+	for key := range quickAnalysisFlowSchema() {
+		tf[key] = d.Get(key)
+	}
+	params := quickAnalysisRequestFromTerraform(tf, applicationId, d.Get("version").(string))
+	analysis, err := quickAnalysis(client, rootAppScopeId, params)
+	if err != nil {
+		return err
+	}
+	d.Set("root_app_scope_id", rootAppScopeId)
+	d.Set("action", analysis.PolicyDecision)
+	policy := analysis.decidingPolicy()
+	if policy == nil {
+		policy = &quickAnalysisPolicy{Rank: "CATCHALL"}
+	}
+	d.Set("policy_id", policy.Id)
+	d.Set("policy_application_id", policy.ApplicationId)
+	d.Set("policy_rank", policy.Rank)
+	d.Set("policy_priority", policy.Priority)
+	d.SetId(fmt.Sprintf("%s:%s:%s:%s", rootAppScopeId, applicationId, params.Version, params.flowId()))
+	return nil
+}
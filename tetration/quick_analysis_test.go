@@ -0,0 +1,40 @@
+package tetration
+
+import (
+	"testing"
+)
+
+func TestDecidingPolicy(t *testing.T) {
+	allow := &quickAnalysisPolicy{Id: "allow", Action: "ALLOW"}
+	deny := &quickAnalysisPolicy{Id: "deny", Action: "DENY"}
+	for _, test := range []struct {
+		analysis quickAnalysisResponse
+		expected *quickAnalysisPolicy
+	}{
+		{quickAnalysisResponse{PolicyDecision: "ALLOW", OutboundPolicy: allow, InboundPolicy: allow}, allow},
+		{quickAnalysisResponse{PolicyDecision: "DENY", OutboundPolicy: allow, InboundPolicy: deny}, deny},
+		{quickAnalysisResponse{PolicyDecision: "DENY", InboundPolicy: deny}, deny},
+		{quickAnalysisResponse{PolicyDecision: "DENY", OutboundPolicy: allow, InboundPolicy: allow}, nil},
+		{quickAnalysisResponse{PolicyDecision: "DENY"}, nil},
+	} {
+		if policy := test.analysis.decidingPolicy(); policy != test.expected {
+			t.Errorf("Expected deciding policy %v for %+v, got %v", test.expected, test.analysis, policy)
+		}
+	}
+}
+
+func TestQuickAnalysisRequestFromTerraform(t *testing.T) {
+	params := quickAnalysisRequestFromTerraform(terraformObject{
+		"consumer_ip": "10.0.0.1",
+		"provider_ip": "10.0.0.2",
+		"protocol":    6,
+		"port":        443,
+	}, "app", "v2")
+	expected := quickAnalysisRequest{ConsumerIp: "10.0.0.1", ProviderIp: "10.0.0.2", Protocol: 6, ProviderPort: 443, ApplicationId: "app", Version: "v2"}
+	if params != expected {
+		t.Errorf("Expected %+v, got %+v", expected, params)
+	}
+	if id := params.flowId(); id != "10.0.0.1:10.0.0.2:6:443" {
+		t.Errorf("Expected flow id 10.0.0.1:10.0.0.2:6:443, got %s", id)
+	}
+}
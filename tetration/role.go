@@ -7,7 +7,6 @@ import (
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/helper/validation"
 
-	client "github.com/tetration-exchange/terraform-go-sdk"
 	tetration "github.com/tetration-exchange/terraform-go-sdk"
 )
 
@@ -74,7 +73,7 @@ func resourceTetrationRole() *schema.Resource {
 
 func resourceTetrationRoleCreate(d *schema.ResourceData, meta interface{}) error {
 
-	client := meta.(client.Client)
+	client := meta.(providerMeta).Client
 	tfUserIds := d.Get("user_ids").(*schema.Set).List()
 	userIds := []string{}
 	for _, tfUserId := range tfUserIds {
@@ -103,7 +102,7 @@ func resourceTetrationRoleCreate(d *schema.ResourceData, meta interface{}) error
 }
 
 func resourceTetrationRoleRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(client.Client)
+	client := meta.(providerMeta).Client
 	role, err := client.GetRole(d.Id())
 	if err != nil {
 		return err
@@ -114,6 +113,6 @@ func resourceTetrationRoleRead(d *schema.ResourceData, meta interface{}) error {
 	return nil
 }
 func resourceTetrationRoleDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(client.Client)
+	client := meta.(providerMeta).Client
 	return client.DeleteRole(d.Id())
 }
@@ -4,7 +4,6 @@ import (
 	"fmt"
 
 	"github.com/hashicorp/terraform/helper/schema"
-	client "github.com/tetration-exchange/terraform-go-sdk"
 	tetration "github.com/tetration-exchange/terraform-go-sdk"
 )
 
@@ -73,9 +72,17 @@ func resourceTetrationScope() *schema.Resource {
 				Description: "Root scope for the tetration installation",
 			},
 			"vrf_id": {
-				Type:        schema.TypeInt,
-				Computed:    true,
-				Description: "ID of the VRF to which scope belongs.",
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"vrf_name"},
+				Description:   "ID of the VRF to which scope belongs. If specified, the parent scope must belong to this VRF.",
+			},
+			"vrf_name": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"vrf_id"},
+				Description:   "(Optional) Name of the VRF the parent scope must belong to, instead of its vrf_id.",
 			},
 			"priority": {
 				Type:     schema.TypeString,
@@ -117,12 +124,16 @@ var requiredCreateScopeParams = []string{"short_name", "parent_app_scope_id", "s
 	"short_query_field", "short_query_value"}
 
 func resourceTetrationScopeCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(client.Client)
+	client := meta.(providerMeta).Client
 	for _, param := range requiredCreateScopeParams {
 		if d.Get(param) == "" {
 			return fmt.Errorf("%s is required but was not provided", param)
 		}
 	}
+	err := validateScopeVrf(client, d)
+	if err != nil {
+		return err
+	}
 	createScopeParams := tetration.CreateScopeRequest{
 		ShortName:        d.Get("short_name").(string),
 		Description:      d.Get("description").(string),
@@ -145,7 +156,7 @@ func resourceTetrationScopeCreate(d *schema.ResourceData, meta interface{}) erro
 }
 
 func resourceTetrationScopeRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(client.Client)
+	client := meta.(providerMeta).Client
 	scope, err := client.DescribeScope(d.Id())
 	if err != nil {
 		return err
@@ -170,7 +181,11 @@ func resourceTetrationScopeRead(d *schema.ResourceData, meta interface{}) error
 }
 
 func resourceTetrationScopeUpdate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(client.Client)
+	client := meta.(providerMeta).Client
+	err := validateScopeVrf(client, d)
+	if err != nil {
+		return err
+	}
 	client.DeleteScope(d.Id())
 	createScopeParams := tetration.CreateScopeRequest{
 		ShortName:        d.Get("short_name").(string),
@@ -193,7 +208,25 @@ func resourceTetrationScopeUpdate(d *schema.ResourceData, meta interface{}) erro
 	return nil
 }
 
+// validateScopeVrf validates that the parent scope belongs to the VRF specified
+// by vrf_id or vrf_name, if any, as scopes belong to the VRF of their root scope.
+func validateScopeVrf(apiClient tetration.Client, d *schema.ResourceData) error {
+	vrfId, err := vrfIdFromTerraform(apiClient, d)
+	if err != nil || vrfId == 0 {
+		return err
+	}
+	parentAppScopeId := d.Get("parent_app_scope_id").(string)
+	parent, err := apiClient.DescribeScope(parentAppScopeId)
+	if err != nil {
+		return fmt.Errorf("Unable to describe scope %s: %s", parentAppScopeId, err)
+	}
+	if parent.VRFId != vrfId {
+		return fmt.Errorf("Parent scope %s belongs to VRF %d, not VRF %d", parent.Name, parent.VRFId, vrfId)
+	}
+	return nil
+}
+
 func resourceTetrationScopeDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(client.Client)
+	client := meta.(providerMeta).Client
 	return client.DeleteScope(d.Id())
 }
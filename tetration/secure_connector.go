@@ -0,0 +1,146 @@
+package tetration
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	client "github.com/tetration-exchange/terraform-go-sdk"
+)
+
+const (
+	// Validity of secure connector tunnel registration tokens.
+	SecureConnectorTokenValidity = time.Hour
+)
+
+func resourceTetrationSecureConnectorToken() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceTetrationSecureConnectorTokenCreate,
+		Read:   resourceTetrationSecureConnectorTokenRead,
+		Delete: resourceTetrationSecureConnectorTokenDelete,
+
+		SchemaVersion: 1,
+
+		Schema: map[string]*schema.Schema{
+			"tenant_name":       tenantNameSchema(true),
+			"root_app_scope_id": rootAppScopeIdSchema(true),
+			"triggers": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				ForceNew: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "(Optional) Arbitrary values that request a new token when changed, e.g. the image of the secure connector client.",
+			},
+			"rotate_certificates": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				ForceNew:    true,
+				Description: "(Optional) Rotate the certificates of the tunnel before requesting the token, forcing the client to register again. Default value is false.",
+			},
+			"token": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "Single-use token to register the secure connector client of the tenant with.",
+			},
+			"expires_at": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "RFC 3339 timestamp after which the token can no longer be used.",
+			},
+			"tunnel_active": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the secure connector tunnel of the tenant is connected, as of the last refresh.",
+			},
+			"tunnel_last_active": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "RFC 3339 timestamp of the last time the secure connector tunnel was connected.",
+			},
+		},
+	}
+}
+
+// secureConnectorStatus describes the state of the secure connector tunnel of a tenant.
+type secureConnectorStatus struct {
+	Active bool `json:"active"`
+	// Unix timestamp of the last time the tunnel was connected.
+	LastActive int64 `json:"last_active"`
+}
+
+// secureConnectorToken returns a new registration token for the secure
+// connector client of the tenant.
+func secureConnectorToken(apiClient client.Client, tenantName string) (string, error) {
+	token, err := doRawRequest(apiClient, http.MethodGet, fmt.Sprintf("/secureconnector/name/%s/token", url.PathEscape(tenantName)), "application/json", nil)
+	return strings.TrimSpace(string(token)), err
+}
+
+// rotateSecureConnectorCertificates rotates the certificates of the secure
+// connector tunnel of the tenant.
+func rotateSecureConnectorCertificates(apiClient client.Client, tenantName string) error {
+	return doRequest(apiClient, http.MethodPost, fmt.Sprintf("/secureconnector/name/%s/rotate_certs", url.PathEscape(tenantName)), nil, nil)
+}
+
+// describeSecureConnectorStatus returns the state of the secure connector
+// tunnel of the tenant.
+func describeSecureConnectorStatus(apiClient client.Client, tenantName string) (secureConnectorStatus, error) {
+	var status secureConnectorStatus
+	err := doRequest(apiClient, http.MethodGet, fmt.Sprintf("/secureconnector/name/%s/status", url.PathEscape(tenantName)), nil, &status)
+	return status, err
+}
+
+func resourceTetrationSecureConnectorTokenCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(providerMeta).Client
+	tenantName, err := resolveTenant(meta.(providerMeta), d.Get("tenant_name").(string), d.Get("root_app_scope_id").(string))
+	if err != nil {
+		return err
+	}
+	if d.Get("rotate_certificates").(bool) {
+		if err := rotateSecureConnectorCertificates(client, tenantName); err != nil {
+			return err
+		}
+	}
+	issuedAt := time.Now()
+	token, err := secureConnectorToken(client, tenantName)
+	if err != nil {
+		return err
+	}
+	if token == "" {
+		return fmt.Errorf("Tetration returned an empty secure connector token for tenant %s", tenantName)
+	}
+	d.SetId(fmt.Sprintf("%s:%d", tenantName, issuedAt.Unix()))
+	d.Set("tenant_name", tenantName)
+	d.Set("token", token)
+	d.Set("expires_at", issuedAt.Add(SecureConnectorTokenValidity).UTC().Format(time.RFC3339))
+	return resourceTetrationSecureConnectorTokenRead(d, meta)
+}
+
+// resourceTetrationSecureConnectorTokenRead refreshes the state of the tunnel,
+// the token itself can't be read back.
+func resourceTetrationSecureConnectorTokenRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(providerMeta).Client
+	status, err := describeSecureConnectorStatus(client, d.Get("tenant_name").(string))
+	if err != nil {
+		return err
+	}
+	lastActive := ""
+	if status.LastActive > 0 {
+		lastActive = time.Unix(status.LastActive, 0).UTC().Format(time.RFC3339)
+	}
+	d.Set("tunnel_active", status.Active)
+	d.Set("tunnel_last_active", lastActive)
+	return nil
+}
+
+// resourceTetrationSecureConnectorTokenDelete only removes the token from
+// state, tokens expire on their own and registered clients stay connected.
+func resourceTetrationSecureConnectorTokenDelete(d *schema.ResourceData, meta interface{}) error {
+	return nil
+}
@@ -0,0 +1,202 @@
+package tetration
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform/configs/hcl2shim"
+	"github.com/hashicorp/terraform/helper/schema"
+	client "github.com/tetration-exchange/terraform-go-sdk"
+	tetration "github.com/tetration-exchange/terraform-go-sdk"
+)
+
+// providedServices lists the public inventory filters of a scope, i.e. the
+// services the scope provides to other applications, returning the filters.
+func providedServices(apiClient client.Client, appScopeId string) ([]tetration.Filter, error) {
+	filters, err := apiClient.ListFilters()
+	if err != nil {
+		return nil, err
+	}
+	var services []tetration.Filter
+	for _, filter := range filters {
+		if filter.Public && filter.AppScopeId == appScopeId {
+			services = append(services, filter)
+		}
+	}
+	sort.SliceStable(services, func(i, j int) bool {
+		return services[i].Name < services[j].Name
+	})
+	return services, nil
+}
+
+// providedServiceFilterId returns the id of the public inventory filter named
+// serviceName in the scope of the application.
+func providedServiceFilterId(apiClient client.Client, applicationId string, serviceName string) (string, error) {
+	application, err := apiClient.DescribeApplication(tetration.DescribeApplicationRequest{
+		ApplicationId: applicationId,
+	})
+	if err != nil {
+		return "", fmt.Errorf("Unable to describe provider application %s: %s", applicationId, err)
+	}
+	services, err := providedServices(apiClient, application.AppScopeId)
+	if err != nil {
+		return "", err
+	}
+	var servicesWithMatchingName []tetration.Filter
+	for _, service := range services {
+		if service.Name == serviceName {
+			servicesWithMatchingName = append(servicesWithMatchingName, service)
+		}
+	}
+	if len(servicesWithMatchingName) == 0 {
+		return "", fmt.Errorf("Application %s does not provide a service %s, the service must be a public inventory filter of scope %s.", applicationId, serviceName, application.AppScopeId)
+	}
+	if len(servicesWithMatchingName) > 1 {
+		return "", fmt.Errorf("More than one service exists with name %s for application %s, please use policy filter id to specify the exact one to use.", serviceName, applicationId)
+	}
+	return servicesWithMatchingName[0].Id, nil
+}
+
+// externalDependenciesFromTerraform resolves the services of other applications
+// referenced by the given policy blocks, returning the dependencies as
+// terraform objects, whether all references were known (as opposed to only
+// known after apply).
+func externalDependenciesFromTerraform(apiClient client.Client, tfPoliciesByRank ...interface{}) ([]interface{}, bool, error) {
+	resolved := make(map[[2]string]string)
+	known := true
+	for _, tfPolicies := range tfPoliciesByRank {
+		for _, tfPolicy := range tfPolicies.([]interface{}) {
+			if tfPolicy == nil {
+				continue
+			}
+			tf := tfPolicy.(terraformObject)
+			applicationId, _ := tf["provider_application_id"].(string)
+			serviceName, _ := tf["provider_service_name"].(string)
+			if applicationId == "" || serviceName == "" {
+				continue
+			}
+			if applicationId == hcl2shim.UnknownVariableValue || serviceName == hcl2shim.UnknownVariableValue {
+				known = false
+				continue
+			}
+			reference := [2]string{applicationId, serviceName}
+			if _, ok := resolved[reference]; ok {
+				continue
+			}
+			filterId, err := providedServiceFilterId(apiClient, applicationId, serviceName)
+			if err != nil {
+				return nil, known, err
+			}
+			resolved[reference] = filterId
+		}
+	}
+	return externalDependenciesToTerraform(resolved), known, nil
+}
+
+// appliedExternalDependencies returns the services referenced by the given policy
+// blocks together with the inventory filter the matching policies use, leaving the
+// filter id empty if the policies of a service disagree.
+func appliedExternalDependencies(policies map[string]applicationPolicy, tfPoliciesByRank ...interface{}) []interface{} {
+	applied := make(map[[2]string]string)
+	for _, tfPolicies := range tfPoliciesByRank {
+		for _, tfPolicy := range tfPolicies.([]interface{}) {
+			if tfPolicy == nil {
+				continue
+			}
+			tf := tfPolicy.(terraformObject)
+			reference := [2]string{tf["provider_application_id"].(string), tf["provider_service_name"].(string)}
+			policy, ok := policies[tf["id"].(string)]
+			if reference[0] == "" || reference[1] == "" || !ok {
+				continue
+			}
+			if filterId, ok := applied[reference]; ok && filterId != policy.ProviderFilterId {
+				applied[reference] = ""
+				continue
+			}
+			applied[reference] = policy.ProviderFilterId
+		}
+	}
+	return externalDependenciesToTerraform(applied)
+}
+
+// externalDependenciesToTerraform returns the services mapped to their
+// inventory filter ids as terraform objects, in a stable order.
+func externalDependenciesToTerraform(filterIds map[[2]string]string) []interface{} {
+	references := make([][2]string, 0, len(filterIds))
+	for reference := range filterIds {
+		references = append(references, reference)
+	}
+	sort.Slice(references, func(i, j int) bool {
+		return strings.Join(references[i][:], "|") < strings.Join(references[j][:], "|")
+	})
+	dependencies := make([]interface{}, 0, len(references))
+	for _, reference := range references {
+		dependencies = append(dependencies, terraformObject{
+			"application_id": reference[0],
+			"service_name":   reference[1],
+			"filter_id":      filterIds[reference],
+		})
+	}
+	return dependencies
+}
+
+// externalDependencyFilterIds maps the services of a list of
+// external dependencies to the inventory filter ids they resolved to.
+func externalDependencyFilterIds(dependencies []interface{}) map[[2]string]string {
+	filterIds := make(map[[2]string]string)
+	for _, dependency := range dependencies {
+		if dependency == nil {
+			continue
+		}
+		tf := dependency.(terraformObject)
+		filterIds[[2]string{tf["application_id"].(string), tf["service_name"].(string)}] = tf["filter_id"].(string)
+	}
+	return filterIds
+}
+
+// updateExternalDependencies points the provider filter of each policy referencing
+// a service of another application to the filter recorded in external_dependencies.
+func updateExternalDependencies(apiClient client.Client, d *schema.ResourceData) error {
+	filterIds := externalDependencyFilterIds(d.Get("external_dependencies").([]interface{}))
+	policies, err := describeApplicationPolicies(apiClient, d.Id())
+	if err != nil {
+		return err
+	}
+	existingPolicies := policies.byId()
+	for _, policyRank := range applicationPolicyRanks {
+		for _, tfPolicy := range d.Get(policyRank.attribute).([]interface{}) {
+			if tfPolicy == nil {
+				continue
+			}
+			tf := tfPolicy.(terraformObject)
+			filterId, ok := filterIds[[2]string{tf["provider_application_id"].(string), tf["provider_service_name"].(string)}]
+			if !ok || filterId == "" {
+				continue
+			}
+			policy, ok := existingPolicies[tf["id"].(string)]
+			if !ok || policy.ProviderFilterId == filterId {
+				continue
+			}
+			_, err := updatePolicy(apiClient, policy.Id, updatePolicyRequest{ProviderFilterId: filterId})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// externalDependenciesKey returns a key identifying a list of
+// external dependencies for comparing them.
+func externalDependenciesKey(dependencies []interface{}) string {
+	var keys []string
+	for _, dependency := range dependencies {
+		if dependency == nil {
+			continue
+		}
+		tf := dependency.(terraformObject)
+		keys = append(keys, fmt.Sprintf("%s|%s|%s", tf["application_id"], tf["service_name"], tf["filter_id"]))
+	}
+	return strings.Join(keys, ",")
+}
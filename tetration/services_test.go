@@ -0,0 +1,29 @@
+package tetration
+
+import (
+	"testing"
+)
+
+func TestAppliedExternalDependencies(t *testing.T) {
+	policies := map[string]applicationPolicy{
+		"p1": {Id: "p1", ProviderFilterId: "f1"},
+		"p2": {Id: "p2", ProviderFilterId: "f1"},
+		"p3": {Id: "p3", ProviderFilterId: "f2"},
+		"p4": {Id: "p4", ProviderFilterId: "f3"},
+	}
+	policy := func(id string, applicationId string, serviceName string) interface{} {
+		return terraformObject{"id": id, "provider_application_id": applicationId, "provider_service_name": serviceName}
+	}
+	dependencies := appliedExternalDependencies(policies,
+		[]interface{}{policy("p1", "app", "db"), policy("p3", "app", "web")},
+		[]interface{}{policy("p2", "app", "db"), policy("p4", "app", "web"), policy("p5", "app", "cache"), policy("p1", "", "")},
+	)
+	expected := "app|db|f1,app|web|"
+	if key := externalDependenciesKey(dependencies); key != expected {
+		t.Errorf("Expected dependencies %s, got %s", expected, key)
+	}
+	filterIds := externalDependencyFilterIds(dependencies)
+	if len(filterIds) != 2 || filterIds[[2]string{"app", "db"}] != "f1" || filterIds[[2]string{"app", "web"}] != "" {
+		t.Errorf("Unexpected filter ids %v", filterIds)
+	}
+}
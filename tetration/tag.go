@@ -2,10 +2,12 @@ package tetration
 
 import (
 	"fmt"
+	"net"
+	"net/url"
+	"strconv"
 	"strings"
 
 	"github.com/hashicorp/terraform/helper/schema"
-	client "github.com/tetration-exchange/terraform-go-sdk"
 	tetration "github.com/tetration-exchange/terraform-go-sdk"
 )
 
@@ -20,21 +22,27 @@ func resourceTetrationTag() *schema.Resource {
 		Read:   resourceTetrationTagRead,
 		Delete: resourceTetrationTagDelete,
 
-		SchemaVersion: 1,
+		CustomizeDiff: resourceTetrationTagCustomizeDiff,
 
-		Schema: map[string]*schema.Schema{
-			"tenant_name": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Default:     "",
-				ForceNew:    true,
-				Description: "Tetration root app scope name.",
+		SchemaVersion: 2,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Type:    resourceTetrationTagV1().CoreConfigSchema().ImpliedType(),
+				Upgrade: resourceTetrationTagStateUpgradeV1,
+				Version: 1,
 			},
+		},
+
+		Schema: map[string]*schema.Schema{
+			"tenant_name":       tenantNameSchema(true),
+			"root_app_scope_id": rootAppScopeIdSchema(true),
 			"ip": {
-				Type:        schema.TypeString,
-				Required:    true,
-				ForceNew:    true,
-				Description: "IPv4/IPv6 address or subnet.",
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				ValidateFunc:     validateTagIp,
+				DiffSuppressFunc: suppressEquivalentTagIpDiffs,
+				Description:      "IPv4/IPv6 address or subnet.",
 			},
 			"attributes": {
 				Type:     schema.TypeMap,
@@ -46,6 +54,14 @@ func resourceTetrationTag() *schema.Resource {
 				},
 				Description: "Key/value map for tagging matching flows and inventory items.",
 			},
+			"annotation_keys": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "(Optional) Enabled annotation keys to validate attributes against, e.g. the enabled_keys of a tetration_annotation_schema. If not specified, attributes are validated on apply against the enabled keys of the annotation schema of the tenant.",
+			},
 		},
 	}
 }
@@ -53,57 +69,240 @@ func resourceTetrationTag() *schema.Resource {
 var requiredCreateTagParams = []string{"ip", "attributes"}
 
 func resourceTetrationTagCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(client.Client)
+	client := meta.(providerMeta).Client
 	for _, param := range requiredCreateTagParams {
 		if d.Get(param) == "" {
 			return fmt.Errorf("%s is required but was not provided", param)
 		}
 	}
-	tenantName := d.Get("tenant_name").(string)
-	if tenantName == "" {
-		tenantURL := client.Config.APIURL
-		// strip protocol and extract the tenant name/subdomain from the url
-		// e.g. https://acme.tetrationpreview.com => acme
-		tenantName = strings.Split(strings.Split(tenantURL, "://")[1], ".")[0]
+	tenantName, err := resolveTenant(meta.(providerMeta), d.Get("tenant_name").(string), d.Get("root_app_scope_id").(string))
+	if err != nil {
+		return err
 	}
 	attributes := d.Get("attributes").(map[string]interface{})
+	err = validateTagAttributes(client, d, tenantName, attributes)
+	if err != nil {
+		return err
+	}
+	ip, err := canonicalTagIp(d.Get("ip").(string))
+	if err != nil {
+		return err
+	}
 	createTagParams := tetration.CreateTagRequest{
 		RootScopeName: tenantName,
-		Ip:            d.Get("ip").(string),
+		Ip:            ip,
 		Attributes:    attributes,
 	}
-	tag, err := client.CreateTag(createTagParams)
+	_, err = client.CreateTag(createTagParams)
 	if err != nil {
 		return err
 	}
-	d.SetId(fmt.Sprintf("%s%s%s", createTagParams.RootScopeName, TagIdDelimter, tag.Ip))
+	d.Set("tenant_name", tenantName)
+	d.SetId(tagId(createTagParams.RootScopeName, ip))
 	return nil
 }
 
+// validateTagAttributes validates that every attribute key is one of annotation_keys
+// or, if annotation_keys is not specified, one of the enabled keys of the annotation
+// schema of the tenant. Keys are not validated if the tenant has no annotation columns.
+func validateTagAttributes(apiClient tetration.Client, d *schema.ResourceData, tenantName string, attributes map[string]interface{}) error {
+	if tfKeys, ok := d.GetOk("annotation_keys"); ok {
+		return validateAnnotationKeys(attributes, stringsFromTerraform(tfKeys.([]interface{})))
+	}
+	columns, err := listAnnotationColumns(apiClient, tenantName)
+	if err != nil {
+		return fmt.Errorf("Unable to read the annotation schema of tenant %s: %s", tenantName, err)
+	}
+	if len(columns) == 0 {
+		return nil
+	}
+	return validateAnnotationKeys(attributes, enabledAnnotationKeys(columns))
+}
+
+// tagId returns the id of the tag of an ip in a tenant. Both components are
+// escaped so that tenant names and IPv6 addresses containing the delimiter
+// can be recovered by tagIdComponents.
+func tagId(tenantName string, ip string) string {
+	return url.QueryEscape(tenantName) + TagIdDelimter + url.QueryEscape(ip)
+}
+
+// tagIdComponents splits a tag id into its tenant name and ip, failing on
+// malformed ids.
+func tagIdComponents(id string) (string, string, error) {
+	components := strings.Split(id, TagIdDelimter)
+	if len(components) != 2 || components[0] == "" || components[1] == "" {
+		return "", "", fmt.Errorf("Invalid tag id %q, expected <tenant_name>%s<ip> with both components query escaped", id, TagIdDelimter)
+	}
+	tenantName, err := url.QueryUnescape(components[0])
+	if err != nil {
+		return "", "", fmt.Errorf("Invalid tenant name in tag id %q: %s", id, err)
+	}
+	ip, err := url.QueryUnescape(components[1])
+	if err != nil {
+		return "", "", fmt.Errorf("Invalid ip in tag id %q: %s", id, err)
+	}
+	return tenantName, ip, nil
+}
+
+// canonicalTagIp returns the canonical form of an IPv4/IPv6 address or subnet,
+// so that equivalent spellings such as 10.0.0.0/24 and 10.0.0.000/24 or
+// 2001:DB8::1 and 2001:db8:0::1 compare equal, failing on invalid addresses.
+// Subnets with host bits set are reduced to their network address.
+func canonicalTagIp(value string) (string, error) {
+	address, prefix := strings.TrimSpace(value), ""
+	if index := strings.Index(address, "/"); index >= 0 {
+		address, prefix = address[:index], address[index+1:]
+	}
+	ip := net.ParseIP(trimIPv4LeadingZeros(address))
+	if ip == nil {
+		return "", fmt.Errorf("%q is not a valid IPv4/IPv6 address or subnet", value)
+	}
+	if ip4 := ip.To4(); ip4 != nil && !strings.Contains(address, ":") {
+		ip = ip4
+	}
+	if prefix == "" {
+		return ip.String(), nil
+	}
+	ones, err := strconv.Atoi(prefix)
+	if err != nil || ones < 0 || ones > len(ip)*8 {
+		return "", fmt.Errorf("%q does not have a valid prefix length", value)
+	}
+	network := ip.Mask(net.CIDRMask(ones, len(ip)*8))
+	return fmt.Sprintf("%s/%d", network, ones), nil
+}
+
+// trimIPv4LeadingZeros removes leading zeros from the octets of a dotted
+// IPv4 address, which net.ParseIP rejects, leaving other values untouched.
+func trimIPv4LeadingZeros(address string) string {
+	octets := strings.Split(address, ".")
+	if len(octets) != 4 || strings.Contains(address, ":") {
+		return address
+	}
+	for index, octet := range octets {
+		trimmed := strings.TrimLeft(octet, "0")
+		if trimmed == "" && octet != "" {
+			trimmed = "0"
+		}
+		octets[index] = trimmed
+	}
+	return strings.Join(octets, ".")
+}
+
+func validateTagIp(value interface{}, key string) ([]string, []error) {
+	canonical, err := canonicalTagIp(value.(string))
+	if err != nil {
+		return nil, []error{fmt.Errorf("%s: %s", key, err)}
+	}
+	address := strings.TrimSpace(value.(string))
+	if index := strings.Index(address, "/"); index >= 0 {
+		configuredIp := net.ParseIP(trimIPv4LeadingZeros(address[:index]))
+		networkIp := net.ParseIP(canonical[:strings.Index(canonical, "/")])
+		if !configuredIp.Equal(networkIp) {
+			return []string{fmt.Sprintf("%s: %q has host bits set and refers to the subnet %s", key, value, canonical)}, nil
+		}
+	}
+	return nil, nil
+}
+
+func suppressEquivalentTagIpDiffs(k, old, new string, d *schema.ResourceData) bool {
+	canonicalOld, err := canonicalTagIp(old)
+	if err != nil {
+		return false
+	}
+	canonicalNew, err := canonicalTagIp(new)
+	return err == nil && canonicalOld == canonicalNew
+}
+
 func resourceTetrationTagRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(client.Client)
-	tagIdComponents := strings.Split(d.Id(), TagIdDelimter)
+	client := meta.(providerMeta).Client
+	tenantName, ip, err := tagIdComponents(d.Id())
+	if err != nil {
+		return err
+	}
 	describeTagRequest := tetration.DescribeTagRequest{
-		RootAppScopeName: tagIdComponents[0],
-		Ip:               tagIdComponents[1],
+		RootAppScopeName: tenantName,
+		Ip:               ip,
 	}
 	attributes := make(map[string]string)
-	err := client.DescribeTag(describeTagRequest, &attributes)
+	err = client.DescribeTag(describeTagRequest, &attributes)
 	if err != nil {
 		return err
 	}
 	d.Set("tenant_name", describeTagRequest.RootAppScopeName)
-	d.Set("ip", describeTagRequest.Ip)
+	// Keep the configured spelling of equivalent addresses
+	if configuredIp, err := canonicalTagIp(d.Get("ip").(string)); err != nil || configuredIp != ip {
+		d.Set("ip", describeTagRequest.Ip)
+	}
 	d.Set("attributes", attributes)
 	return nil
 }
 
 func resourceTetrationTagDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(client.Client)
-	tagIdComponents := strings.Split(d.Id(), TagIdDelimter)
+	client := meta.(providerMeta).Client
+	tenantName, ip, err := tagIdComponents(d.Id())
+	if err != nil {
+		return err
+	}
 	deleteTagRequest := tetration.DeleteTagRequest{
-		RootAppScopeName: tagIdComponents[0],
-		Ip:               tagIdComponents[1],
+		RootAppScopeName: tenantName,
+		Ip:               ip,
 	}
 	return client.DeleteTag(deleteTagRequest)
 }
+
+// resourceTetrationTagCustomizeDiff validates at plan time that every attribute key
+// is one of annotation_keys, if given. Tags without annotation_keys are validated
+// against the annotation schema on apply, so that planning them doesn't need to read it.
+func resourceTetrationTagCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("attributes") {
+		return nil
+	}
+	attributes := d.Get("attributes").(map[string]interface{})
+	// Keys of an annotation schema created in the same plan are validated on apply
+	if !d.NewValueKnown("annotation_keys") {
+		return nil
+	}
+	if tfKeys, ok := d.GetOk("annotation_keys"); ok {
+		return validateAnnotationKeys(attributes, stringsFromTerraform(tfKeys.([]interface{})))
+	}
+	return nil
+}
+
+// resourceTetrationTagV1 returns the schema of tags with version 1 ids,
+// which joined the tenant name and ip unescaped.
+func resourceTetrationTagV1() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"tenant_name":       {Type: schema.TypeString, Optional: true, Computed: true},
+			"root_app_scope_id": {Type: schema.TypeString, Optional: true},
+			"ip":                {Type: schema.TypeString, Required: true},
+			"attributes": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+// resourceTetrationTagStateUpgradeV1 rewrites version 1 tag ids to the escaped
+// encoding of tagId, preferring the tenant_name and ip attributes over the
+// ambiguous old id when splitting ids of IPv6 addresses or tenant names with colons.
+func resourceTetrationTagStateUpgradeV1(rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	id, _ := rawState["id"].(string)
+	tenantName, _ := rawState["tenant_name"].(string)
+	ip, _ := rawState["ip"].(string)
+	if tenantName == "" || ip == "" {
+		components := strings.SplitN(id, TagIdDelimter, 2)
+		if len(components) != 2 {
+			return rawState, fmt.Errorf("Unable to upgrade tag id %q, expected <tenant_name>%s<ip>", id, TagIdDelimter)
+		}
+		tenantName, ip = components[0], components[1]
+	}
+	if canonicalIp, err := canonicalTagIp(ip); err == nil {
+		ip = canonicalIp
+	}
+	rawState["id"] = tagId(tenantName, ip)
+	return rawState, nil
+}
@@ -0,0 +1,93 @@
+package tetration
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+	tetration "github.com/tetration-exchange/terraform-go-sdk"
+)
+
+func dataSourceTetrationTag() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceTetrationTagRead,
+
+		Schema: map[string]*schema.Schema{
+			"tenant_name":       tenantNameSchema(false),
+			"root_app_scope_id": rootAppScopeIdSchema(false),
+			"ip": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateTagIp,
+				Description:  "IPv4/IPv6 address or subnet.",
+			},
+			"include_inventory": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "(Optional) Also read the inventory attributes of the address or subnet. Default value is false.",
+			},
+			"attributes": {
+				Type:     schema.TypeMap,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "User uploaded key/value annotations of the address or subnet.",
+			},
+			"inventory_attributes": {
+				Type:     schema.TypeMap,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "Inventory attributes (such as host_name, os and orchestrator labels) shared by all inventory items of the address or subnet, only read if include_inventory is true.",
+			},
+			"hostname": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Hostname of the inventory items, only read if include_inventory is true.",
+			},
+			"os": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Operating system of the inventory items, only read if include_inventory is true.",
+			},
+		},
+	}
+}
+
+func dataSourceTetrationTagRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(providerMeta).Client
+	tenantName, err := resolveTenant(meta.(providerMeta), d.Get("tenant_name").(string), d.Get("root_app_scope_id").(string))
+	if err != nil {
+		return err
+	}
+	ip, err := canonicalTagIp(d.Get("ip").(string))
+	if err != nil {
+		return err
+	}
+	attributes := make(map[string]string)
+	err = client.DescribeTag(tetration.DescribeTagRequest{
+		RootAppScopeName: tenantName,
+		Ip:               ip,
+	}, &attributes)
+	if err != nil {
+		return err
+	}
+	inventoryAttributes := map[string]string{}
+	if d.Get("include_inventory").(bool) {
+		items, err := searchInventory(client, inventorySearchRequest{
+			Filter:    inventoryAddressFilter(ip),
+			ScopeName: tenantName,
+		}, 0)
+		if err != nil {
+			return err
+		}
+		inventoryAttributes = mergeInventoryItems(items)
+	}
+	d.Set("tenant_name", tenantName)
+	d.Set("attributes", attributes)
+	d.Set("inventory_attributes", inventoryAttributes)
+	d.Set("hostname", inventoryAttributes["host_name"])
+	d.Set("os", inventoryAttributes["os"])
+	d.SetId(tagId(tenantName, ip))
+	return nil
+}
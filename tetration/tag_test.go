@@ -0,0 +1,124 @@
+package tetration
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestCanonicalTagIp(t *testing.T) {
+	equivalent := map[string]string{
+		"10.0.0.0/24":      "10.0.0.0/24",
+		"10.0.0.000/24":    "10.0.0.0/24",
+		"010.001.000.010":  "10.1.0.10",
+		"2001:DB8:0::1":    "2001:db8::1",
+		"2001:db8::/32":    "2001:db8::/32",
+		" 192.168.1.1/32 ": "192.168.1.1/32",
+		"10.0.0.1/24":      "10.0.0.0/24",
+		"2001:db8::1/32":   "2001:db8::/32",
+	}
+	for value, expected := range equivalent {
+		canonical, err := canonicalTagIp(value)
+		if err != nil {
+			t.Errorf("Unexpected error for %q: %s", value, err)
+		} else if canonical != expected {
+			t.Errorf("Expected %q to be canonicalized to %s, got %s", value, expected, canonical)
+		}
+	}
+	for _, value := range []string{"", "10.0.0", "10.0.0.256", "10.0.0.1/33", "2001:db8::1/129", "host.example.com"} {
+		if _, err := canonicalTagIp(value); err == nil {
+			t.Errorf("Expected %q to be invalid", value)
+		}
+	}
+}
+
+func TestValidateTagIp(t *testing.T) {
+	warnings, errors := validateTagIp("10.0.0.1/24", "ip")
+	if len(errors) > 0 || len(warnings) != 1 {
+		t.Errorf("Expected a warning for a subnet with host bits set, got %v and %v", warnings, errors)
+	}
+	for _, value := range []string{"10.0.0.0/24", "010.000.000.000/24", "10.0.0.1", "2001:db8::/32"} {
+		if warnings, errors := validateTagIp(value, "ip"); len(warnings) > 0 || len(errors) > 0 {
+			t.Errorf("Unexpected warnings %v and errors %v for %q", warnings, errors, value)
+		}
+	}
+	if _, errors := validateTagIp("10.0.0.256", "ip"); len(errors) != 1 {
+		t.Errorf("Expected an error for an invalid address, got %v", errors)
+	}
+}
+
+func TestTagIdRoundTrip(t *testing.T) {
+	for _, components := range [][2]string{
+		{"acme", "10.0.0.0/24"},
+		{"tenant:with:colons", "2001:db8::/32"},
+		{"tenant with spaces%", "fe80::1"},
+	} {
+		tenantName, ip, err := tagIdComponents(tagId(components[0], components[1]))
+		if err != nil {
+			t.Errorf("Unexpected error for %v: %s", components, err)
+		} else if tenantName != components[0] || ip != components[1] {
+			t.Errorf("Expected %v, got [%s %s]", components, tenantName, ip)
+		}
+	}
+}
+
+func TestTagStateUpgradeV1(t *testing.T) {
+	rawState := map[string]interface{}{
+		"id":          "acme:2001:DB8::1",
+		"tenant_name": "acme",
+		"ip":          "2001:DB8::1",
+	}
+	upgraded, err := resourceTetrationTagStateUpgradeV1(rawState, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if id := upgraded["id"]; id != "acme:2001%3Adb8%3A%3A1" {
+		t.Errorf("Expected escaped id, got %s", id)
+	}
+	upgraded, err = resourceTetrationTagStateUpgradeV1(map[string]interface{}{"id": "acme:10.0.0.0/24"}, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if id := upgraded["id"]; id != "acme:10.0.0.0%2F24" {
+		t.Errorf("Expected escaped id, got %s", id)
+	}
+}
+
+func TestValidateTagAttributes(t *testing.T) {
+	apiClient, closeServer := testSearchClient(t, "/assets/cmdb/annotations/acme", map[string]interface{}{
+		"": []annotationColumn{{Name: "env", Enabled: true}, {Name: "owner", Enabled: false}},
+	})
+	defer closeServer()
+	for _, test := range []struct {
+		raw      map[string]interface{}
+		expected string
+	}{
+		{map[string]interface{}{"attributes": map[string]interface{}{"env": "prod"}}, ""},
+		{map[string]interface{}{"attributes": map[string]interface{}{"env": "prod", "owner": "ops"}}, "Annotation keys [owner] are not enabled"},
+		{map[string]interface{}{"attributes": map[string]interface{}{"owner": "ops"}, "annotation_keys": []interface{}{"owner"}}, ""},
+		{map[string]interface{}{"attributes": map[string]interface{}{"env": "prod"}, "annotation_keys": []interface{}{"owner"}}, "Annotation keys [env] are not enabled"},
+	} {
+		d := schema.TestResourceDataRaw(t, resourceTetrationTag().Schema, test.raw)
+		err := validateTagAttributes(apiClient, d, "acme", d.Get("attributes").(map[string]interface{}))
+		if test.expected == "" && err != nil {
+			t.Errorf("Expected no error for %v, got %s", test.raw, err)
+		}
+		if test.expected != "" && (err == nil || !strings.Contains(err.Error(), test.expected)) {
+			t.Errorf("Expected error %q for %v, got %v", test.expected, test.raw, err)
+		}
+	}
+}
+
+func TestValidateTagAttributesWithoutAnnotationColumns(t *testing.T) {
+	apiClient, closeServer := testSearchClient(t, "/assets/cmdb/annotations/acme", map[string]interface{}{
+		"": []annotationColumn{},
+	})
+	defer closeServer()
+	d := schema.TestResourceDataRaw(t, resourceTetrationTag().Schema, map[string]interface{}{
+		"attributes": map[string]interface{}{"env": "prod"},
+	})
+	if err := validateTagAttributes(apiClient, d, "acme", d.Get("attributes").(map[string]interface{})); err != nil {
+		t.Errorf("Expected no error without annotation columns, got %s", err)
+	}
+}
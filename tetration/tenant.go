@@ -0,0 +1,103 @@
+package tetration
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	client "github.com/tetration-exchange/terraform-go-sdk"
+)
+
+// tenantNameSchema returns the schema of the tenant_name attribute resolved by resolveTenant.
+func tenantNameSchema(forceNew bool) *schema.Schema {
+	return &schema.Schema{
+		Type:          schema.TypeString,
+		Optional:      true,
+		Computed:      true,
+		ForceNew:      forceNew,
+		ConflictsWith: []string{"root_app_scope_id"},
+		Description:   "Tetration root app scope name. Defaults to the tenant of root_app_scope_id or the default_tenant of the provider.",
+	}
+}
+
+// rootAppScopeIdSchema returns the schema of the root_app_scope_id attribute resolved by resolveTenant.
+func rootAppScopeIdSchema(forceNew bool) *schema.Schema {
+	return &schema.Schema{
+		Type:          schema.TypeString,
+		Optional:      true,
+		ForceNew:      forceNew,
+		ConflictsWith: []string{"tenant_name"},
+		Description:   "(Optional) ID of the root app scope to resolve the tenant from, instead of tenant_name.",
+	}
+}
+
+// resolveTenant returns the name of the root scope (tenant) to use, in order of
+// precedence from the explicitly given tenant name, the given root scope id,
+// the provider default tenant or the subdomain of the API URL, validating that
+// the root scope exists.
+func resolveTenant(meta providerMeta, tenantName string, rootAppScopeId string) (string, error) {
+	apiClient := meta.Client
+	if tenantName == "" && rootAppScopeId != "" {
+		return tenantForRootAppScopeId(apiClient, rootAppScopeId)
+	}
+	if tenantName == "" {
+		tenantName = meta.DefaultTenant
+	}
+	if tenantName == "" {
+		var err error
+		tenantName, err = tenantFromAPIURL(apiClient.Config.APIURL)
+		if err != nil {
+			return "", err
+		}
+	}
+	return tenantName, validateTenant(apiClient, tenantName)
+}
+
+// tenantForRootAppScopeId returns the name of the root scope with the given id.
+func tenantForRootAppScopeId(apiClient client.Client, rootAppScopeId string) (string, error) {
+	scope, err := apiClient.DescribeScope(rootAppScopeId)
+	if err != nil {
+		return "", fmt.Errorf("Unable to describe root scope %s: %s", rootAppScopeId, err)
+	}
+	if scope.ParentAppScopeId != "" {
+		return "", fmt.Errorf("Scope %s (%s) is not a root scope, its root scope is %s", scope.Name, rootAppScopeId, scope.RootAppScopeId)
+	}
+	return scope.Name, nil
+}
+
+// validateTenant validates that a root scope with the given name exists.
+func validateTenant(apiClient client.Client, tenantName string) error {
+	scopes, err := apiClient.ListScopes()
+	if err != nil {
+		return fmt.Errorf("Unable to list scopes to validate tenant %s: %s", tenantName, err)
+	}
+	var rootScopeNames []string
+	for _, scope := range scopes {
+		if scope.ParentAppScopeId != "" {
+			continue
+		}
+		if scope.Name == tenantName {
+			return nil
+		}
+		rootScopeNames = append(rootScopeNames, scope.Name)
+	}
+	return fmt.Errorf("No root scope (tenant) named %s exists, available root scopes are [%s]", tenantName, strings.Join(rootScopeNames, ", "))
+}
+
+// tenantFromAPIURL derives the tenant name from the subdomain of a hosted
+// Tetration API URL, e.g. https://acme.tetrationpreview.com => acme, failing
+// on URLs that don't identify a tenant.
+func tenantFromAPIURL(apiURL string) (string, error) {
+	parsedURL, err := url.Parse(apiURL)
+	if err != nil || parsedURL.Hostname() == "" {
+		return "", fmt.Errorf("Unable to derive the tenant from API URL %q, please configure default_tenant for the provider or tenant_name", apiURL)
+	}
+	host := parsedURL.Hostname()
+	labels := strings.Split(host, ".")
+	if net.ParseIP(host) != nil || len(labels) < 3 {
+		return "", fmt.Errorf("Unable to derive the tenant from API URL host %s, please configure default_tenant for the provider or tenant_name", host)
+	}
+	return labels[0], nil
+}
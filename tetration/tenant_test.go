@@ -0,0 +1,23 @@
+package tetration
+
+import (
+	"testing"
+)
+
+func TestTenantFromAPIURL(t *testing.T) {
+	tenantName, err := tenantFromAPIURL("https://acme.tetrationpreview.com/")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if tenantName != "acme" {
+		t.Errorf("Expected tenant acme, got %s", tenantName)
+	}
+}
+
+func TestTenantFromAPIURLErrorsForURLsWithoutTenant(t *testing.T) {
+	for _, apiURL := range []string{"", "acme.tetrationpreview.com", "https://10.0.0.1", "https://tetration.internal:8443"} {
+		if tenantName, err := tenantFromAPIURL(apiURL); err == nil {
+			t.Errorf("Expected error deriving tenant from %q, got %s", apiURL, tenantName)
+		}
+	}
+}
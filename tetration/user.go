@@ -2,7 +2,6 @@ package tetration
 
 import (
 	"github.com/hashicorp/terraform/helper/schema"
-	client "github.com/tetration-exchange/terraform-go-sdk"
 	tetration "github.com/tetration-exchange/terraform-go-sdk"
 )
 
@@ -68,7 +67,7 @@ func resourceTetrationUser() *schema.Resource {
 }
 
 func resourceTetrationUserCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(client.Client)
+	client := meta.(providerMeta).Client
 	enableExistingUser := d.Get("enable_existing").(bool)
 	if enableExistingUser {
 		users, err := client.ListUsers(tetration.ListUsersRequest{
@@ -117,7 +116,7 @@ func resourceTetrationUserCreate(d *schema.ResourceData, meta interface{}) error
 }
 
 func resourceTetrationUserRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(client.Client)
+	client := meta.(providerMeta).Client
 	user, err := client.DescribeUser(d.Id())
 	if err != nil {
 		return err
@@ -132,6 +131,6 @@ func resourceTetrationUserRead(d *schema.ResourceData, meta interface{}) error {
 }
 
 func resourceTetrationUserDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(client.Client)
+	client := meta.(providerMeta).Client
 	return client.DeleteUser(d.Id())
 }
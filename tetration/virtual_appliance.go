@@ -0,0 +1,294 @@
+package tetration
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+	client "github.com/tetration-exchange/terraform-go-sdk"
+)
+
+const (
+	IngestVirtualApplianceType = "TETRATION_INGEST"
+	EdgeVirtualApplianceType   = "TETRATION_EDGE"
+)
+
+var (
+	ValidVirtualApplianceTypes = []string{IngestVirtualApplianceType, EdgeVirtualApplianceType}
+)
+
+func resourceTetrationVirtualAppliance() *schema.Resource {
+	return &schema.Resource{
+		Create:        resourceTetrationVirtualApplianceCreate,
+		Update:        resourceTetrationVirtualApplianceUpdate,
+		Read:          resourceTetrationVirtualApplianceRead,
+		Delete:        resourceTetrationVirtualApplianceDelete,
+		CustomizeDiff: resourceTetrationVirtualApplianceCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		SchemaVersion: 1,
+
+		Schema: map[string]*schema.Schema{
+			"root_app_scope_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "ID of the root scope the virtual appliance belongs to.",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "User-specified name for the virtual appliance.",
+			},
+			"type": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice(ValidVirtualApplianceTypes, false),
+				Description:  fmt.Sprintf("Type of the virtual appliance, one of [%s]. Ingest appliances host flow connectors such as ERSPAN and NetFlow, edge appliances host alert and inventory connectors such as ISE and ServiceNow.", strings.Join(ValidVirtualApplianceTypes, ", ")),
+			},
+			"interface": {
+				Type:        schema.TypeList,
+				Required:    true,
+				ForceNew:    true,
+				MinItems:    1,
+				Description: "Network configuration of the interfaces of the virtual appliance, in the order of its network adapters.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"ip": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.SingleIP(),
+							Description:  "IP address of the interface.",
+						},
+						"prefix_length": {
+							Type:         schema.TypeInt,
+							Required:     true,
+							ValidateFunc: validation.IntBetween(1, 128),
+							Description:  "Length of the network prefix of the interface, 1 to 32 for IPv4 addresses and 1 to 128 for IPv6 addresses.",
+						},
+						"gateway": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validation.SingleIP(),
+							Description:  "(Optional) IP address of the default gateway of the interface.",
+						},
+					},
+				},
+			},
+			"dns_servers": {
+				Type:     schema.TypeList,
+				Optional: true,
+				ForceNew: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.SingleIP(),
+				},
+				Description: "(Optional) IP addresses of the DNS servers of the virtual appliance.",
+			},
+			"dns_search_domains": {
+				Type:     schema.TypeList,
+				Optional: true,
+				ForceNew: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "(Optional) DNS search domains of the virtual appliance.",
+			},
+			"http_proxy": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "(Optional) URL of the HTTP proxy the virtual appliance connects to Tetration through.",
+			},
+			"status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Deployment status of the virtual appliance, e.g. PENDING_REGISTRATION or ACTIVE, as of the last refresh.",
+			},
+			"last_heartbeat": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "RFC 3339 timestamp of the last heartbeat of the virtual appliance.",
+			},
+			"connector_ids": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "IDs of the connectors deployed on the virtual appliance.",
+			},
+		},
+	}
+}
+
+// virtualApplianceInterface describes the network configuration of an interface of a virtual appliance.
+type virtualApplianceInterface struct {
+	Ip           string `json:"ip"`
+	PrefixLength int    `json:"prefix_length"`
+	Gateway      string `json:"gateway,omitempty"`
+}
+
+// virtualAppliance describes a virtual machine deployed on-premises to host connectors.
+type virtualAppliance struct {
+	Id               string                      `json:"id,omitempty"`
+	RootAppScopeId   string                      `json:"root_app_scope_id,omitempty"`
+	Name             string                      `json:"name"`
+	Type             string                      `json:"type,omitempty"`
+	IpConfig         []virtualApplianceInterface `json:"ip_config,omitempty"`
+	DnsServers       []string                    `json:"dns_servers,omitempty"`
+	DnsSearchDomains []string                    `json:"dns_search_domains,omitempty"`
+	HttpProxy        string                      `json:"http_proxy,omitempty"`
+	Status           string                      `json:"status,omitempty"`
+	// Unix timestamp of the last heartbeat of the appliance.
+	LastHeartbeatAt int64    `json:"last_heartbeat_at,omitempty"`
+	ConnectorIds    []string `json:"connector_ids,omitempty"`
+}
+
+// createVirtualAppliance registers a virtual appliance, returning the
+// registered appliance.
+func createVirtualAppliance(apiClient client.Client, params virtualAppliance) (virtualAppliance, error) {
+	var created virtualAppliance
+	err := doRequest(apiClient, http.MethodPost, "/appliances", params, &created)
+	return created, err
+}
+
+func describeVirtualAppliance(apiClient client.Client, applianceId string) (virtualAppliance, error) {
+	var described virtualAppliance
+	err := doRequest(apiClient, http.MethodGet, fmt.Sprintf("/appliances/%s", applianceId), nil, &described)
+	return described, err
+}
+
+func updateVirtualAppliance(apiClient client.Client, applianceId string, name string) error {
+	return doRequest(apiClient, http.MethodPut, fmt.Sprintf("/appliances/%s", applianceId), virtualAppliance{Name: name}, nil)
+}
+
+func deleteVirtualAppliance(apiClient client.Client, applianceId string) error {
+	return doRequest(apiClient, http.MethodDelete, fmt.Sprintf("/appliances/%s", applianceId), nil, nil)
+}
+
+// virtualApplianceInterfacesFromTerraform returns the interfaces of the resource.
+func virtualApplianceInterfacesFromTerraform(tfInterfaces []interface{}) ([]virtualApplianceInterface, error) {
+	interfaces := make([]virtualApplianceInterface, 0, len(tfInterfaces))
+	for _, tfInterface := range tfInterfaces {
+		applianceInterface, err := virtualApplianceInterfaceFromTerraform(tfInterface.(terraformObject))
+		if err != nil {
+			return nil, err
+		}
+		interfaces = append(interfaces, applianceInterface)
+	}
+	return interfaces, nil
+}
+
+// virtualApplianceInterfaceFromTerraform returns an interface block as an interface,
+// validating its prefix length against its address family and that its gateway is
+// within its network.
+func virtualApplianceInterfaceFromTerraform(tf terraformObject) (virtualApplianceInterface, error) {
+	applianceInterface := virtualApplianceInterface{
+		Ip:           tf["ip"].(string),
+		PrefixLength: tf["prefix_length"].(int),
+		Gateway:      tf["gateway"].(string),
+	}
+	if ip := net.ParseIP(applianceInterface.Ip); ip != nil && ip.To4() != nil && applianceInterface.PrefixLength > 32 {
+		return applianceInterface, fmt.Errorf("Invalid prefix length %d of IPv4 interface %s, expected 1 to 32", applianceInterface.PrefixLength, applianceInterface.Ip)
+	}
+	_, network, err := net.ParseCIDR(fmt.Sprintf("%s/%d", applianceInterface.Ip, applianceInterface.PrefixLength))
+	if err != nil {
+		return applianceInterface, fmt.Errorf("Invalid interface %s/%d: %s", applianceInterface.Ip, applianceInterface.PrefixLength, err)
+	}
+	if applianceInterface.Gateway != "" && !network.Contains(net.ParseIP(applianceInterface.Gateway)) {
+		return applianceInterface, fmt.Errorf("Gateway %s is not in the network %s of interface %s", applianceInterface.Gateway, network, applianceInterface.Ip)
+	}
+	return applianceInterface, nil
+}
+
+// resourceTetrationVirtualApplianceCustomizeDiff validates the interfaces at plan
+// time, skipping interfaces with values that are only known after apply.
+func resourceTetrationVirtualApplianceCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("interface") {
+		return nil
+	}
+	for index, tfInterface := range d.Get("interface").([]interface{}) {
+		if tfInterface == nil || !blockAttributesKnown(d, fmt.Sprintf("interface.%d", index), []string{"ip", "prefix_length", "gateway"}) {
+			continue
+		}
+		if _, err := virtualApplianceInterfaceFromTerraform(tfInterface.(terraformObject)); err != nil {
+			return fmt.Errorf("interface.%d: %s", index, err)
+		}
+	}
+	return nil
+}
+
+func resourceTetrationVirtualApplianceCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(providerMeta).Client
+	interfaces, err := virtualApplianceInterfacesFromTerraform(d.Get("interface").([]interface{}))
+	if err != nil {
+		return err
+	}
+	created, err := createVirtualAppliance(client, virtualAppliance{
+		RootAppScopeId:   d.Get("root_app_scope_id").(string),
+		Name:             d.Get("name").(string),
+		Type:             d.Get("type").(string),
+		IpConfig:         interfaces,
+		DnsServers:       stringsFromTerraform(d.Get("dns_servers").([]interface{})),
+		DnsSearchDomains: stringsFromTerraform(d.Get("dns_search_domains").([]interface{})),
+		HttpProxy:        d.Get("http_proxy").(string),
+	})
+	if err != nil {
+		return err
+	}
+	d.SetId(created.Id)
+	return resourceTetrationVirtualApplianceRead(d, meta)
+}
+
+func resourceTetrationVirtualApplianceUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(providerMeta).Client
+	err := updateVirtualAppliance(client, d.Id(), d.Get("name").(string))
+	if err != nil {
+		return err
+	}
+	return resourceTetrationVirtualApplianceRead(d, meta)
+}
+
+func resourceTetrationVirtualApplianceRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(providerMeta).Client
+	described, err := describeVirtualAppliance(client, d.Id())
+	if err != nil {
+		return err
+	}
+	tfInterfaces := make([]interface{}, 0, len(described.IpConfig))
+	for _, applianceInterface := range described.IpConfig {
+		tfInterfaces = append(tfInterfaces, terraformObject{
+			"ip":            applianceInterface.Ip,
+			"prefix_length": applianceInterface.PrefixLength,
+			"gateway":       applianceInterface.Gateway,
+		})
+	}
+	lastHeartbeat := ""
+	if described.LastHeartbeatAt > 0 {
+		lastHeartbeat = time.Unix(described.LastHeartbeatAt, 0).UTC().Format(time.RFC3339)
+	}
+	d.Set("root_app_scope_id", described.RootAppScopeId)
+	d.Set("name", described.Name)
+	d.Set("type", described.Type)
+	d.Set("interface", tfInterfaces)
+	d.Set("dns_servers", described.DnsServers)
+	d.Set("dns_search_domains", described.DnsSearchDomains)
+	d.Set("http_proxy", described.HttpProxy)
+	d.Set("status", described.Status)
+	d.Set("last_heartbeat", lastHeartbeat)
+	d.Set("connector_ids", described.ConnectorIds)
+	return nil
+}
+
+func resourceTetrationVirtualApplianceDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(providerMeta).Client
+	return deleteVirtualAppliance(client, d.Id())
+}
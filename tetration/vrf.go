@@ -0,0 +1,217 @@
+package tetration
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+	client "github.com/tetration-exchange/terraform-go-sdk"
+)
+
+func resourceTetrationVrf() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceTetrationVrfCreate,
+		Update: resourceTetrationVrfUpdate,
+		Read:   resourceTetrationVrfRead,
+		Delete: resourceTetrationVrfDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		SchemaVersion: 1,
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "User-specified name for the VRF.",
+			},
+			"vrf_id": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "(Optional) Numeric ID of the VRF, as used by vrf_id in scope and filter queries. Assigned by Tetration if not specified.",
+			},
+			"tenant_id": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "(Optional) ID of the tenant the VRF belongs to. Defaults to the default tenant of the cluster.",
+			},
+			"switch_vrfs": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "(Optional) Names of the VRFs of switches and agents whose flows are mapped to this VRF.",
+			},
+			"apply_monitoring_rules": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "(Optional) Whether collection rules are applied to the agents of the VRF. Default value is false.",
+			},
+			"tenant_name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Name of the tenant the VRF belongs to.",
+			},
+			"root_app_scope_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "ID of the root scope created for the VRF.",
+			},
+		},
+	}
+}
+
+// vrf describes a virtual routing and forwarding instance that separates overlapping address spaces.
+type vrf struct {
+	Id                   int      `json:"id,omitempty"`
+	Name                 string   `json:"name"`
+	TenantId             int      `json:"tenant_id,omitempty"`
+	TenantName           string   `json:"tenant_name,omitempty"`
+	RootAppScopeId       string   `json:"root_app_scope_id,omitempty"`
+	SwitchVrfs           []string `json:"switch_vrfs"`
+	ApplyMonitoringRules bool     `json:"apply_monitoring_rules"`
+}
+
+// createVrf creates a VRF, returning the created VRF.
+func createVrf(apiClient client.Client, params vrf) (vrf, error) {
+	var created vrf
+	err := doRequest(apiClient, http.MethodPost, "/vrfs", params, &created)
+	return created, err
+}
+
+func describeVrf(apiClient client.Client, vrfId int) (vrf, error) {
+	var described vrf
+	err := doRequest(apiClient, http.MethodGet, fmt.Sprintf("/vrfs/%d", vrfId), nil, &described)
+	return described, err
+}
+
+func listVrfs(apiClient client.Client) ([]vrf, error) {
+	var vrfs []vrf
+	err := doRequest(apiClient, http.MethodGet, "/vrfs", nil, &vrfs)
+	return vrfs, err
+}
+
+func updateVrf(apiClient client.Client, vrfId int, params vrf) error {
+	return doRequest(apiClient, http.MethodPut, fmt.Sprintf("/vrfs/%d", vrfId), params, nil)
+}
+
+func deleteVrf(apiClient client.Client, vrfId int) error {
+	return doRequest(apiClient, http.MethodDelete, fmt.Sprintf("/vrfs/%d", vrfId), nil, nil)
+}
+
+// vrfIdFromResourceId returns the numeric VRF id of a resource id.
+func vrfIdFromResourceId(id string) (int, error) {
+	vrfId, err := strconv.Atoi(id)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid VRF id %q, expected a number", id)
+	}
+	return vrfId, nil
+}
+
+// vrfIdForName returns the id of the VRF with the given name, failing if no
+// or several VRFs have the name.
+func vrfIdForName(apiClient client.Client, name string) (int, error) {
+	vrfs, err := listVrfs(apiClient)
+	if err != nil {
+		return 0, err
+	}
+	var vrfIds []int
+	for _, v := range vrfs {
+		if v.Name == name {
+			vrfIds = append(vrfIds, v.Id)
+		}
+	}
+	if len(vrfIds) == 0 {
+		return 0, fmt.Errorf("No VRF exists with name %s.", name)
+	}
+	if len(vrfIds) > 1 {
+		return 0, fmt.Errorf("More than one VRF exists with name %s, please use vrf_id to specify the exact one to use.", name)
+	}
+	return vrfIds[0], nil
+}
+
+// vrfIdFromTerraform returns the vrf_id of a scope or filter, resolving
+// vrf_name if specified instead, or 0 if neither is specified.
+func vrfIdFromTerraform(apiClient client.Client, d *schema.ResourceData) (int, error) {
+	if name := d.Get("vrf_name").(string); name != "" {
+		return vrfIdForName(apiClient, name)
+	}
+	return d.Get("vrf_id").(int), nil
+}
+
+// vrfToTerraform sets the attributes of a VRF on the resource or data source.
+func vrfToTerraform(d *schema.ResourceData, described vrf) {
+	d.Set("name", described.Name)
+	d.Set("vrf_id", described.Id)
+	d.Set("tenant_id", described.TenantId)
+	d.Set("tenant_name", described.TenantName)
+	d.Set("root_app_scope_id", described.RootAppScopeId)
+	d.Set("switch_vrfs", described.SwitchVrfs)
+	d.Set("apply_monitoring_rules", described.ApplyMonitoringRules)
+}
+
+func resourceTetrationVrfCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(providerMeta).Client
+	created, err := createVrf(client, vrf{
+		Id:                   d.Get("vrf_id").(int),
+		Name:                 d.Get("name").(string),
+		TenantId:             d.Get("tenant_id").(int),
+		SwitchVrfs:           stringsFromTerraform(d.Get("switch_vrfs").([]interface{})),
+		ApplyMonitoringRules: d.Get("apply_monitoring_rules").(bool),
+	})
+	if err != nil {
+		return err
+	}
+	d.SetId(strconv.Itoa(created.Id))
+	return resourceTetrationVrfRead(d, meta)
+}
+
+func resourceTetrationVrfUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(providerMeta).Client
+	vrfId, err := vrfIdFromResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+	err = updateVrf(client, vrfId, vrf{
+		Name:                 d.Get("name").(string),
+		SwitchVrfs:           stringsFromTerraform(d.Get("switch_vrfs").([]interface{})),
+		ApplyMonitoringRules: d.Get("apply_monitoring_rules").(bool),
+	})
+	if err != nil {
+		return err
+	}
+	return resourceTetrationVrfRead(d, meta)
+}
+
+func resourceTetrationVrfRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(providerMeta).Client
+	vrfId, err := vrfIdFromResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+	described, err := describeVrf(client, vrfId)
+	if err != nil {
+		return err
+	}
+	vrfToTerraform(d, described)
+	return nil
+}
+
+func resourceTetrationVrfDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(providerMeta).Client
+	vrfId, err := vrfIdFromResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+	return deleteVrf(client, vrfId)
+}
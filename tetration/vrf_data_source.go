@@ -0,0 +1,97 @@
+package tetration
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourceTetrationVrf() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceTetrationVrfRead,
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"vrf_id"},
+				Description:   "(Optional) Name of the VRF to look up. Required unless vrf_id is specified.",
+			},
+			"vrf_id": {
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"name"},
+				Description:   "(Optional) Numeric ID of the VRF to look up. Required unless name is specified.",
+			},
+			"tenant_id": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Computed:    true,
+				Description: "(Optional) ID of the tenant to look up the VRF in, for VRF names used by several tenants.",
+			},
+			"tenant_name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Name of the tenant the VRF belongs to.",
+			},
+			"root_app_scope_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "ID of the root scope of the VRF.",
+			},
+			"switch_vrfs": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "Names of the VRFs of switches and agents whose flows are mapped to the VRF.",
+			},
+			"apply_monitoring_rules": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether collection rules are applied to the agents of the VRF.",
+			},
+		},
+	}
+}
+
+func dataSourceTetrationVrfRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(providerMeta).Client
+	name := d.Get("name").(string)
+	vrfId := d.Get("vrf_id").(int)
+	tenantId, tenantIdSet := d.GetOk("tenant_id")
+	if name == "" && vrfId == 0 {
+		return fmt.Errorf("One of name or vrf_id must be specified")
+	}
+	vrfs, err := listVrfs(client)
+	if err != nil {
+		return err
+	}
+	var matches []vrf
+	for _, v := range vrfs {
+		if vrfId != 0 && v.Id != vrfId {
+			continue
+		}
+		if name != "" && v.Name != name {
+			continue
+		}
+		if tenantIdSet && v.TenantId != tenantId.(int) {
+			continue
+		}
+		matches = append(matches, v)
+	}
+	switch len(matches) {
+	case 0:
+		return fmt.Errorf("No VRF matching name %q and vrf_id %d exists", name, vrfId)
+	case 1:
+	default:
+		return fmt.Errorf("%d VRFs named %q exist, specify tenant_id or vrf_id", len(matches), name)
+	}
+	d.SetId(strconv.Itoa(matches[0].Id))
+	vrfToTerraform(d, matches[0])
+	return nil
+}
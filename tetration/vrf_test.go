@@ -0,0 +1,39 @@
+package tetration
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestVrfIdForName(t *testing.T) {
+	apiClient, closeServer := testSearchClient(t, "/vrfs", map[string]interface{}{
+		"": []vrf{{Id: 1, Name: "Default"}, {Id: 2, Name: "Production"}, {Id: 3, Name: "Shared"}, {Id: 4, Name: "Shared"}},
+	})
+	defer closeServer()
+	if vrfId, err := vrfIdForName(apiClient, "Production"); err != nil || vrfId != 2 {
+		t.Errorf("Expected VRF 2, got %d (%v)", vrfId, err)
+	}
+	if _, err := vrfIdForName(apiClient, "Missing"); err == nil || !strings.Contains(err.Error(), "No VRF exists") {
+		t.Errorf("Expected an error for a missing VRF, got %v", err)
+	}
+	if _, err := vrfIdForName(apiClient, "Shared"); err == nil || !strings.Contains(err.Error(), "More than one VRF") {
+		t.Errorf("Expected an error for an ambiguous VRF name, got %v", err)
+	}
+}
+
+func TestVrfRestrictedQuery(t *testing.T) {
+	query, err := vrfRestrictedQuery(json.RawMessage(`{"type": "eq", "field": "ip", "value": "10.0.0.1"}`), 700056)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(query, &decoded); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	encoded, _ := json.Marshal(decoded)
+	expected := `{"filters":[{"field":"vrf_id","type":"eq","value":700056},{"field":"ip","type":"eq","value":"10.0.0.1"}],"type":"and"}`
+	if string(encoded) != expected {
+		t.Errorf("Expected %s, got %s", expected, encoded)
+	}
+}